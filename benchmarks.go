@@ -0,0 +1,397 @@
+package tools_build
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"maps"
+	"math"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/goyek/goyek/v3"
+	"github.com/spf13/afero"
+)
+
+// Copyright © 2026 Marc Johnson (marc.johnson27591@gmail.com)
+
+const (
+	// DefaultBenchmarkBaseline is the baseline file used when none is named
+	DefaultBenchmarkBaseline = "benchmarks.txt"
+	// DefaultBenchmarkCount is the number of times each benchmark is run when
+	// no count is specified; enough samples to detect significant changes
+	DefaultBenchmarkCount = 6
+	defaultBenchmarkAlpha = 0.05
+)
+
+var (
+	// BenchBaselineFlag is a flag that names the benchmark baseline file
+	BenchBaselineFlag = flag.String(
+		"benchbaseline",
+		DefaultBenchmarkBaseline,
+		"set to the name of the file in which benchmark baseline results are kept")
+	// BenchCountFlag is a flag that sets the number of times each benchmark is run
+	BenchCountFlag = flag.Int(
+		"benchcount",
+		DefaultBenchmarkCount,
+		"set to the number of times each benchmark is run")
+	// BenchMaxRegressionFlag is a flag that sets the largest tolerated benchmark regression, in percent
+	BenchMaxRegressionFlag = flag.Float64(
+		"benchmaxregression",
+		0,
+		"set to the largest statistically significant benchmark regression, in percent, that does not fail the build")
+	// BenchPatternFlag is a flag that selects the benchmarks to run
+	BenchPatternFlag = flag.String(
+		"benchpattern",
+		".",
+		"set to a regular expression selecting the benchmarks to run")
+	// BenchTimeFlag is a flag that sets how long each benchmark runs
+	BenchTimeFlag = flag.String(
+		"benchtime",
+		"",
+		"set to a duration (such as 2s) or an iteration count (such as 100x) for each benchmark run")
+	// BenchUpdateFlag is a flag that replaces the benchmark baseline with the current results
+	BenchUpdateFlag = flag.Bool(
+		"benchupdate",
+		false,
+		"set to replace the benchmark baseline with the current results")
+)
+
+// BenchmarkOptions controls how Benchmarks runs and judges benchmarks
+type BenchmarkOptions struct {
+	// Pattern is a regular expression selecting the benchmarks to run; empty
+	// runs all benchmarks
+	Pattern string
+	// Count is the number of times each benchmark is run; zero means
+	// DefaultBenchmarkCount
+	Count int
+	// Benchtime is the -benchtime value, such as "2s" or "100x"; empty leaves
+	// the go test default
+	Benchtime string
+	// Packages are the packages to benchmark; if empty, "./..." is used
+	Packages []string
+	// BaselineFile names the file, relative to WorkingDir(), holding the
+	// baseline results; empty means DefaultBenchmarkBaseline
+	BaselineFile string
+	// UpdateBaseline replaces the baseline with the current results instead
+	// of comparing against it
+	UpdateBaseline bool
+	// MaxRegression is the largest statistically significant regression, in
+	// percent, that is tolerated
+	MaxRegression float64
+	// Alpha is the significance level for comparisons; zero means 0.05
+	Alpha float64
+}
+
+// FlagBenchmarkOptions returns the BenchmarkOptions specified by the command
+// line flags
+func FlagBenchmarkOptions() BenchmarkOptions {
+	return BenchmarkOptions{
+		Pattern:        *BenchPatternFlag,
+		Count:          *BenchCountFlag,
+		Benchtime:      *BenchTimeFlag,
+		BaselineFile:   *BenchBaselineFlag,
+		UpdateBaseline: *BenchUpdateFlag,
+		MaxRegression:  *BenchMaxRegressionFlag,
+	}
+}
+
+func (o BenchmarkOptions) command() string {
+	pattern := o.Pattern
+	if pattern == "" {
+		pattern = "."
+	}
+	count := o.Count
+	if count <= 0 {
+		count = DefaultBenchmarkCount
+	}
+	cmdParts := []string{"go", "test", "-run=^$", "-bench=" + quoteArg(pattern), fmt.Sprintf("-count=%d", count)}
+	if o.Benchtime != "" {
+		cmdParts = append(cmdParts, "-benchtime="+quoteArg(o.Benchtime))
+	}
+	cmdParts = append(cmdParts, "-benchmem")
+	cmdParts = append(cmdParts, TestOptions{Packages: o.Packages}.packages()...)
+	return strings.Join(cmdParts, " ")
+}
+
+func (o BenchmarkOptions) baselineFile() string {
+	if o.BaselineFile == "" {
+		return DefaultBenchmarkBaseline
+	}
+	return o.BaselineFile
+}
+
+// Benchmarks runs the benchmarks as directed by the options. If there is no
+// baseline file, or options.UpdateBaseline is set, the results are saved as the
+// new baseline; otherwise, the results are compared with the baseline, using
+// the Mann-Whitney U test to decide which differences are statistically
+// significant. Returns false on failure, including a baseline file that cannot
+// be read, or if any benchmark has regressed by more than options.MaxRegression
+// percent
+func Benchmarks(a *goyek.A, options BenchmarkOptions) bool {
+	baselineFile := options.baselineFile()
+	if isIllegalFileName(baselineFile) {
+		fmt.Fprintf(os.Stderr, "cannot accept %q as a valid benchmark baseline file name\n", baselineFile)
+		return false
+	}
+	printIt("running benchmarks")
+	state, stdout, stderr := cmdCapture(a, directedCommand{command: options.command(), dir: WorkingDir()})
+	if stdout != "" {
+		printIt(stdout)
+	}
+	if stderr != "" {
+		printIt(stderr)
+	}
+	if !state {
+		return false
+	}
+	baselinePath := filepath.Join(WorkingDir(), baselineFile)
+	if exists, _ := afero.Exists(BuildFS, baselinePath); options.UpdateBaseline || !exists {
+		fmt.Printf("saving benchmark results as the baseline in %q\n", baselineFile)
+		return writeWorkingFile(baselineFile, []byte(stdout+"\n"))
+	}
+	baseline, err := afero.ReadFile(BuildFS, baselinePath)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "error %v reading %q\n", err, baselinePath)
+		return false
+	}
+	alpha := options.Alpha
+	if alpha <= 0 {
+		alpha = defaultBenchmarkAlpha
+	}
+	comparisons := compareBenchmarks(parseBenchmarks(string(baseline)), parseBenchmarks(stdout), alpha)
+	printIt(benchmarkComparisonTable(comparisons))
+	regressed := false
+	for _, c := range comparisons {
+		if c.significant && c.regression() > options.MaxRegression {
+			regressed = true
+			fmt.Fprintf(os.Stderr, "%s (%s) regressed by %.1f%%\n", c.name, c.unit, c.regression())
+		}
+	}
+	return !regressed
+}
+
+// benchmarkSamples maps benchmark names, qualified by package, to the samples
+// measured in each unit
+type benchmarkSamples map[string]map[string][]float64
+
+// parseBenchmarks parses 'go test -bench' output; benchmark names are
+// qualified by the package named in the most recent 'pkg:' line
+func parseBenchmarks(output string) benchmarkSamples {
+	samples := benchmarkSamples{}
+	pkg := ""
+	for line := range strings.SplitSeq(output, "\n") {
+		if name, found := strings.CutPrefix(line, "pkg: "); found {
+			pkg = strings.TrimSpace(name)
+			continue
+		}
+		fields := strings.Fields(line)
+		if len(fields) < 4 || !strings.HasPrefix(fields[0], "Benchmark") || len(fields)%2 != 0 {
+			continue
+		}
+		if _, err := strconv.Atoi(fields[1]); err != nil {
+			continue
+		}
+		name := fields[0]
+		if pkg != "" {
+			name = pkg + " " + name
+		}
+		for index := 2; index+1 < len(fields); index += 2 {
+			value, err := strconv.ParseFloat(fields[index], 64)
+			if err != nil {
+				continue
+			}
+			if samples[name] == nil {
+				samples[name] = map[string][]float64{}
+			}
+			samples[name][fields[index+1]] = append(samples[name][fields[index+1]], value)
+		}
+	}
+	return samples
+}
+
+// benchmarkComparison compares one benchmark measurement against its baseline
+type benchmarkComparison struct {
+	name        string
+	unit        string
+	oldMedian   float64
+	newMedian   float64
+	pValue      float64
+	significant bool
+}
+
+// delta returns the percentage change of the median
+func (bc benchmarkComparison) delta() float64 {
+	if bc.oldMedian == 0 {
+		return 0
+	}
+	return 100 * (bc.newMedian - bc.oldMedian) / bc.oldMedian
+}
+
+// regression returns the percentage by which the benchmark got worse;
+// throughput units (those ending in "/s") get worse as they decrease, and all
+// other units get worse as they increase
+func (bc benchmarkComparison) regression() float64 {
+	if strings.HasSuffix(bc.unit, "/s") {
+		return -bc.delta()
+	}
+	return bc.delta()
+}
+
+func compareBenchmarks(baseline, current benchmarkSamples, alpha float64) []benchmarkComparison {
+	comparisons := make([]benchmarkComparison, 0)
+	for _, name := range slices.Sorted(maps.Keys(current)) {
+		for _, unit := range slices.Sorted(maps.Keys(current[name])) {
+			oldSamples, found := baseline[name][unit]
+			if !found {
+				continue
+			}
+			newSamples := current[name][unit]
+			p := mannWhitneyPValue(oldSamples, newSamples)
+			comparisons = append(comparisons, benchmarkComparison{
+				name:        name,
+				unit:        unit,
+				oldMedian:   median(oldSamples),
+				newMedian:   median(newSamples),
+				pValue:      p,
+				significant: p < alpha,
+			})
+		}
+	}
+	return comparisons
+}
+
+func benchmarkComparisonTable(comparisons []benchmarkComparison) string {
+	if len(comparisons) == 0 {
+		return "no benchmarks in common with the baseline"
+	}
+	buffer := &bytes.Buffer{}
+	w := tabwriter.NewWriter(buffer, 0, 8, 2, ' ', 0)
+	fmt.Fprintln(w, "benchmark\tunit\tbaseline\tcurrent\tdelta\tp")
+	for _, c := range comparisons {
+		delta := "~"
+		if c.significant {
+			delta = fmt.Sprintf("%+.1f%%", c.delta())
+		}
+		fmt.Fprintf(w, "%s\t%s\t%.4g\t%.4g\t%s\t%.3f\n", c.name, c.unit, c.oldMedian, c.newMedian, delta, c.pValue)
+	}
+	_ = w.Flush()
+	return EatTrailingEOL(buffer.String())
+}
+
+func median(samples []float64) float64 {
+	if len(samples) == 0 {
+		return 0
+	}
+	sorted := slices.Sorted(slices.Values(samples))
+	middle := len(sorted) / 2
+	if len(sorted)%2 == 0 {
+		return (sorted[middle-1] + sorted[middle]) / 2
+	}
+	return sorted[middle]
+}
+
+// mannWhitneyPValue returns the two-sided p-value of the Mann-Whitney U test
+// of the hypothesis that the two samples come from the same distribution. The
+// exact distribution of U is used for small samples without ties; otherwise,
+// the normal approximation, corrected for ties and continuity, is used
+func mannWhitneyPValue(x, y []float64) float64 {
+	n1, n2 := len(x), len(y)
+	if n1 == 0 || n2 == 0 {
+		return 1
+	}
+	type observation struct {
+		value float64
+		first bool
+	}
+	all := make([]observation, 0, n1+n2)
+	for _, v := range x {
+		all = append(all, observation{value: v, first: true})
+	}
+	for _, v := range y {
+		all = append(all, observation{value: v})
+	}
+	slices.SortFunc(all, func(o1, o2 observation) int {
+		switch {
+		case o1.value < o2.value:
+			return -1
+		case o1.value > o2.value:
+			return 1
+		}
+		return 0
+	})
+	var rankSum, tieCorrection float64
+	ties := false
+	for start := 0; start < len(all); {
+		end := start
+		for end+1 < len(all) && all[end+1].value == all[start].value {
+			end++
+		}
+		rank := float64(start+end)/2 + 1
+		for index := start; index <= end; index++ {
+			if all[index].first {
+				rankSum += rank
+			}
+		}
+		if t := float64(end - start + 1); t > 1 {
+			ties = true
+			tieCorrection += t*t*t - t
+		}
+		start = end + 1
+	}
+	u := rankSum - float64(n1*(n1+1))/2
+	if !ties && n1+n2 <= 50 {
+		return exactMannWhitneyPValue(n1, n2, u)
+	}
+	n := float64(n1 + n2)
+	variance := float64(n1*n2) / 12 * ((n + 1) - tieCorrection/(n*(n-1)))
+	if variance <= 0 {
+		return 1
+	}
+	z := (math.Abs(u-float64(n1*n2)/2) - 0.5) / math.Sqrt(variance)
+	if z < 0 {
+		return 1
+	}
+	return math.Erfc(z / math.Sqrt2)
+}
+
+// exactMannWhitneyPValue computes the two-sided p-value of the U statistic
+// from its exact distribution, counting the arrangements of the two samples
+// that produce each value of U
+func exactMannWhitneyPValue(n1, n2 int, u float64) float64 {
+	// counts[i][j][k] is the number of arrangements of i and j observations with U = k
+	counts := make([][][]float64, n1+1)
+	for i := 0; i <= n1; i++ {
+		counts[i] = make([][]float64, n2+1)
+		for j := 0; j <= n2; j++ {
+			counts[i][j] = make([]float64, i*j+1)
+			if i == 0 || j == 0 {
+				counts[i][j][0] = 1
+				continue
+			}
+			for k := 0; k <= i*j; k++ {
+				if k >= j {
+					counts[i][j][k] += counts[i-1][j][k-j]
+				}
+				if k <= i*(j-1) {
+					counts[i][j][k] += counts[i][j-1][k]
+				}
+			}
+		}
+	}
+	var total, lower, upper float64
+	for k, count := range counts[n1][n2] {
+		total += count
+		if float64(k) <= u {
+			lower += count
+		}
+		if float64(k) >= u {
+			upper += count
+		}
+	}
+	return math.Min(1, 2*math.Min(lower, upper)/total)
+}
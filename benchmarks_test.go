@@ -0,0 +1,259 @@
+package tools_build
+
+import (
+	"io/fs"
+	"math"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/goyek/goyek/v3"
+	"github.com/spf13/afero"
+)
+
+// Copyright © 2026 Marc Johnson (marc.johnson27591@gmail.com)
+
+func benchmarkOutput(nsPerOp ...int) string {
+	lines := []string{"goos: linux", "goarch: amd64", "pkg: example.com/m"}
+	for _, ns := range nsPerOp {
+		lines = append(lines, "BenchmarkX-8   \t 1000000\t      "+strconv.Itoa(ns)+" ns/op\t      16 B/op\t       1 allocs/op")
+	}
+	lines = append(lines, "PASS", "ok  \texample.com/m\t1.234s")
+	return strings.Join(lines, "\n")
+}
+
+// unreadableFS is a filesystem whose files exist, but cannot be opened
+type unreadableFS struct {
+	afero.Fs
+}
+
+func (unreadableFS) Open(name string) (afero.File, error) {
+	return nil, &fs.PathError{Op: "open", Path: name, Err: fs.ErrPermission}
+}
+
+func TestBenchmarks(t *testing.T) {
+	originalBuildFS := BuildFS
+	originalCachedWorkingDir := CachedWorkingDir
+	originalCmdCapture := cmdCapture
+	defer func() {
+		BuildFS = originalBuildFS
+		CachedWorkingDir = originalCachedWorkingDir
+		cmdCapture = originalCmdCapture
+	}()
+	CachedWorkingDir = "work"
+	baseline := benchmarkOutput(100, 101, 102, 100, 101, 102)
+	tests := map[string]struct {
+		options      BenchmarkOptions
+		baseline     string
+		unreadable   bool
+		runSucceeds  bool
+		output       string
+		wantCommand  string
+		wantBaseline string
+		want         bool
+	}{
+		"bad baseline name": {
+			options: BenchmarkOptions{BaselineFile: "../bench.txt"},
+			want:    false,
+		},
+		"run fails": {
+			options:     BenchmarkOptions{},
+			runSucceeds: false,
+			wantCommand: "go test -run=^$ -bench=. -count=6 -benchmem ./...",
+			want:        false,
+		},
+		"no baseline": {
+			options:      BenchmarkOptions{Pattern: "X", Count: 10, Benchtime: "100x", Packages: []string{"./a"}},
+			runSucceeds:  true,
+			output:       baseline,
+			wantCommand:  "go test -run=^$ -bench=X -count=10 -benchtime=100x -benchmem ./a",
+			wantBaseline: baseline + "\n",
+			want:         true,
+		},
+		"unreadable baseline": {
+			options:      BenchmarkOptions{},
+			baseline:     baseline,
+			unreadable:   true,
+			runSucceeds:  true,
+			output:       benchmarkOutput(200, 201, 202, 200, 201, 202),
+			wantCommand:  "go test -run=^$ -bench=. -count=6 -benchmem ./...",
+			wantBaseline: baseline,
+			want:         false,
+		},
+		"update baseline": {
+			options:      BenchmarkOptions{UpdateBaseline: true},
+			baseline:     baseline,
+			runSucceeds:  true,
+			output:       benchmarkOutput(200, 201, 202, 200, 201, 202),
+			wantCommand:  "go test -run=^$ -bench=. -count=6 -benchmem ./...",
+			wantBaseline: benchmarkOutput(200, 201, 202, 200, 201, 202) + "\n",
+			want:         true,
+		},
+		"no significant change": {
+			options:      BenchmarkOptions{},
+			baseline:     baseline,
+			runSucceeds:  true,
+			output:       benchmarkOutput(101, 100, 102, 101, 100, 103),
+			wantCommand:  "go test -run=^$ -bench=. -count=6 -benchmem ./...",
+			wantBaseline: baseline,
+			want:         true,
+		},
+		"significant regression": {
+			options:      BenchmarkOptions{MaxRegression: 10},
+			baseline:     baseline,
+			runSucceeds:  true,
+			output:       benchmarkOutput(150, 151, 152, 150, 151, 152),
+			wantCommand:  "go test -run=^$ -bench=. -count=6 -benchmem ./...",
+			wantBaseline: baseline,
+			want:         false,
+		},
+		"tolerated regression": {
+			options:      BenchmarkOptions{MaxRegression: 60},
+			baseline:     baseline,
+			runSucceeds:  true,
+			output:       benchmarkOutput(150, 151, 152, 150, 151, 152),
+			wantCommand:  "go test -run=^$ -bench=. -count=6 -benchmem ./...",
+			wantBaseline: baseline,
+			want:         true,
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			BuildFS = afero.NewMemMapFs()
+			_ = BuildFS.MkdirAll("work", dirMode)
+			if tt.baseline != "" {
+				_ = afero.WriteFile(BuildFS, "work/benchmarks.txt", []byte(tt.baseline), fileMode)
+			}
+			memFS := BuildFS
+			if tt.unreadable {
+				BuildFS = unreadableFS{Fs: memFS}
+			}
+			var gotCommand string
+			cmdCapture = func(_ *goyek.A, dC directedCommand) (bool, string, string) {
+				gotCommand = dC.command
+				return tt.runSucceeds, tt.output, ""
+			}
+			if got := Benchmarks(nil, tt.options); got != tt.want {
+				t.Errorf("Benchmarks() = %v, want %v", got, tt.want)
+			}
+			if gotCommand != tt.wantCommand {
+				t.Errorf("Benchmarks() command = %q, want %q", gotCommand, tt.wantCommand)
+			}
+			if tt.wantBaseline != "" {
+				content, _ := afero.ReadFile(memFS, "work/benchmarks.txt")
+				if string(content) != tt.wantBaseline {
+					t.Errorf("Benchmarks() baseline = %q, want %q", content, tt.wantBaseline)
+				}
+			}
+		})
+	}
+}
+
+func Test_parseBenchmarks(t *testing.T) {
+	output := "" +
+		"pkg: example.com/m\n" +
+		"BenchmarkA-8   \t 100\t 12.5 ns/op\t 3 B/op\t 1 allocs/op\n" +
+		"BenchmarkA-8   \t 100\t 13.5 ns/op\t 3 B/op\t 1 allocs/op\n" +
+		"BenchmarkBogus text\n" +
+		"BenchmarkB-8   \t many\t 1 ns/op\n" +
+		"pkg: example.com/m/sub\n" +
+		"BenchmarkC-8   \t 100\t 99 MB/s\t 5 ns/op\n"
+	want := benchmarkSamples{
+		"example.com/m BenchmarkA-8": {
+			"ns/op":     {12.5, 13.5},
+			"B/op":      {3, 3},
+			"allocs/op": {1, 1},
+		},
+		"example.com/m/sub BenchmarkC-8": {
+			"MB/s":  {99},
+			"ns/op": {5},
+		},
+	}
+	if got := parseBenchmarks(output); !reflect.DeepEqual(got, want) {
+		t.Errorf("parseBenchmarks() = %v, want %v", got, want)
+	}
+}
+
+func Test_benchmarkComparison_regression(t *testing.T) {
+	tests := map[string]struct {
+		comparison benchmarkComparison
+		want       float64
+	}{
+		"slower":          {comparison: benchmarkComparison{unit: "ns/op", oldMedian: 100, newMedian: 120}, want: 20},
+		"faster":          {comparison: benchmarkComparison{unit: "ns/op", oldMedian: 100, newMedian: 80}, want: -20},
+		"less throughput": {comparison: benchmarkComparison{unit: "MB/s", oldMedian: 100, newMedian: 80}, want: 20},
+		"no baseline":     {comparison: benchmarkComparison{unit: "ns/op", oldMedian: 0, newMedian: 80}, want: 0},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			if got := tt.comparison.regression(); got != tt.want {
+				t.Errorf("regression() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_median(t *testing.T) {
+	tests := map[string]struct {
+		samples []float64
+		want    float64
+	}{
+		"empty": {samples: nil, want: 0},
+		"odd":   {samples: []float64{3, 1, 2}, want: 2},
+		"even":  {samples: []float64{4, 1, 3, 2}, want: 2.5},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			if got := median(tt.samples); got != tt.want {
+				t.Errorf("median() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_mannWhitneyPValue(t *testing.T) {
+	tests := map[string]struct {
+		x    []float64
+		y    []float64
+		want float64
+	}{
+		"empty": {
+			x:    nil,
+			y:    []float64{1},
+			want: 1,
+		},
+		"completely separated": {
+			x:    []float64{1, 2, 3, 4, 5, 6},
+			y:    []float64{7, 8, 9, 10, 11, 12},
+			want: 2.0 / 924,
+		},
+		"completely separated, reversed": {
+			x:    []float64{7, 8, 9, 10, 11, 12},
+			y:    []float64{1, 2, 3, 4, 5, 6},
+			want: 2.0 / 924,
+		},
+		"interleaved": {
+			x:    []float64{1, 3, 5},
+			y:    []float64{2, 4, 6},
+			want: 0.7,
+		},
+		"identical": {
+			x:    []float64{5, 5, 5},
+			y:    []float64{5, 5, 5},
+			want: 1,
+		},
+		"ties": {
+			x:    []float64{1, 1, 2, 2, 3, 3},
+			y:    []float64{4, 4, 5, 5, 6, 6},
+			want: 0.004701,
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			if got := mannWhitneyPValue(tt.x, tt.y); math.Abs(got-tt.want) > 1e-4 {
+				t.Errorf("mannWhitneyPValue() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
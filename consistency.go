@@ -0,0 +1,90 @@
+package tools_build
+
+import (
+	"fmt"
+	"maps"
+	"os"
+	"slices"
+	"strings"
+	"text/tabwriter"
+)
+
+// Copyright © 2026 Marc Johnson (marc.johnson27591@gmail.com)
+
+// moduleSetting records the value of a go.mod setting in each module
+// declaring it, keyed by module name
+type moduleSetting map[string]string
+
+// consistent returns true if every module has the same value
+func (ms moduleSetting) consistent() bool {
+	values := map[string]bool{}
+	for _, value := range ms {
+		values[value] = true
+	}
+	return len(values) <= 1
+}
+
+// ModuleConsistency parses every go.mod file in the working directory tree and
+// reports differences between modules: mismatched go directives, mismatched
+// toolchain directives, and dependencies required at different versions by
+// different modules. Returns false on failure, or if failOnMismatch is true
+// and any difference is found
+func ModuleConsistency(failOnMismatch bool) bool {
+	modules, ok := findModules()
+	if !ok {
+		return false
+	}
+	goVersions := moduleSetting{}
+	toolchains := moduleSetting{}
+	dependencies := map[string]moduleSetting{}
+	for _, module := range modules {
+		name := module.name()
+		goVersions[name] = ""
+		if module.file.Go != nil {
+			goVersions[name] = module.file.Go.Version
+		}
+		toolchains[name] = ""
+		if module.file.Toolchain != nil {
+			toolchains[name] = module.file.Toolchain.Name
+		}
+		for _, requirement := range module.file.Require {
+			if dependencies[requirement.Mod.Path] == nil {
+				dependencies[requirement.Mod.Path] = moduleSetting{}
+			}
+			dependencies[requirement.Mod.Path][name] = requirement.Mod.Version
+		}
+	}
+	mismatches := make([]string, 0)
+	if !goVersions.consistent() {
+		mismatches = append(mismatches, "go directives differ:\n"+settingTable(goVersions))
+	}
+	if !toolchains.consistent() {
+		mismatches = append(mismatches, "toolchain directives differ:\n"+settingTable(toolchains))
+	}
+	for _, dependency := range slices.Sorted(maps.Keys(dependencies)) {
+		if !dependencies[dependency].consistent() {
+			mismatches = append(mismatches, fmt.Sprintf("versions of %s differ:\n%s", dependency,
+				settingTable(dependencies[dependency])))
+		}
+	}
+	if len(mismatches) == 0 {
+		printIt(fmt.Sprintf("%d modules are consistent", len(modules)))
+		return true
+	}
+	printIt(strings.Join(mismatches, "\n"))
+	if failOnMismatch {
+		fmt.Fprintf(os.Stderr, "%d module inconsistencies found\n", len(mismatches))
+		return false
+	}
+	return true
+}
+
+func settingTable(setting moduleSetting) string {
+	builder := &strings.Builder{}
+	w := tabwriter.NewWriter(builder, 0, 0, 2, ' ', 0)
+	for _, module := range slices.Sorted(maps.Keys(setting)) {
+		fmt.Fprintf(w, "%s\t%s\n", module, orDash(setting[module]))
+	}
+	_ = w.Flush()
+	return "\t" + strings.ReplaceAll(strings.TrimSuffix(builder.String(), "\n"), "\n", "\n\t")
+}
@@ -0,0 +1,93 @@
+package tools_build
+
+import (
+	"testing"
+
+	"github.com/spf13/afero"
+)
+
+// Copyright © 2026 Marc Johnson (marc.johnson27591@gmail.com)
+
+func TestModuleConsistency(t *testing.T) {
+	originalBuildFS := BuildFS
+	originalCachedWorkingDir := CachedWorkingDir
+	defer func() {
+		BuildFS = originalBuildFS
+		CachedWorkingDir = originalCachedWorkingDir
+	}()
+	tests := map[string]struct {
+		goMods         map[string]string
+		failOnMismatch bool
+		want           bool
+	}{
+		"unparseable": {
+			goMods:         map[string]string{"work/go.mod": "modulo m\n"},
+			failOnMismatch: true,
+			want:           false,
+		},
+		"consistent": {
+			goMods: map[string]string{
+				"work/go.mod":   "module m\n\ngo 1.26\n\nrequire example.com/a v1.0.0\n",
+				"work/b/go.mod": "module m/b\n\ngo 1.26\n\nrequire (\n\texample.com/a v1.0.0\n\texample.com/c v1.0.0\n)\n",
+			},
+			failOnMismatch: true,
+			want:           true,
+		},
+		"inconsistent go directive": {
+			goMods: map[string]string{
+				"work/go.mod":   "module m\n\ngo 1.26\n",
+				"work/b/go.mod": "module m/b\n\ngo 1.25\n",
+			},
+			failOnMismatch: true,
+			want:           false,
+		},
+		"inconsistent toolchain": {
+			goMods: map[string]string{
+				"work/go.mod":   "module m\n\ngo 1.26\n\ntoolchain go1.26.1\n",
+				"work/b/go.mod": "module m/b\n\ngo 1.26\n",
+			},
+			failOnMismatch: true,
+			want:           false,
+		},
+		"inconsistent dependency": {
+			goMods: map[string]string{
+				"work/go.mod":   "module m\n\ngo 1.26\n\nrequire example.com/a v1.0.0\n",
+				"work/b/go.mod": "module m/b\n\ngo 1.26\n\nrequire example.com/a v1.1.0\n",
+			},
+			failOnMismatch: true,
+			want:           false,
+		},
+		"inconsistent, report only": {
+			goMods: map[string]string{
+				"work/go.mod":   "module m\n\ngo 1.26\n\nrequire example.com/a v1.0.0\n",
+				"work/b/go.mod": "module m/b\n\ngo 1.25\n\nrequire example.com/a v1.1.0\n",
+			},
+			failOnMismatch: false,
+			want:           true,
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			BuildFS = afero.NewMemMapFs()
+			CachedWorkingDir = "work"
+			_ = BuildFS.MkdirAll("work/b", dirMode)
+			for file, content := range tt.goMods {
+				_ = afero.WriteFile(BuildFS, file, []byte(content), fileMode)
+			}
+			if got := ModuleConsistency(tt.failOnMismatch); got != tt.want {
+				t.Errorf("ModuleConsistency() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_settingTable(t *testing.T) {
+	setting := moduleSetting{"example.com/m": "1.26", "example.com/m/tools": "", "x": "1.25"}
+	want := "" +
+		"\texample.com/m        1.26\n" +
+		"\texample.com/m/tools  -\n" +
+		"\tx                    1.25"
+	if got := settingTable(setting); got != want {
+		t.Errorf("settingTable() = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,386 @@
+package tools_build
+
+import (
+	"bytes"
+	"cmp"
+	"flag"
+	"fmt"
+	"maps"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/goyek/goyek/v3"
+	"github.com/spf13/afero"
+)
+
+// Copyright © 2026 Marc Johnson (marc.johnson27591@gmail.com)
+
+var (
+	// CoverageCoberturaFlag is a flag that names a file to which coverage data is written in Cobertura XML format
+	CoverageCoberturaFlag = flag.String(
+		"coveragecobertura",
+		"",
+		"set to the name of a file to which coverage data will be written in Cobertura XML format")
+	// CoverageFuncFlag is a flag that prints a per-function coverage summary, lowest coverage first
+	CoverageFuncFlag = flag.Bool(
+		"coveragefunc",
+		false,
+		"set to print a per-function coverage summary, sorted by lowest coverage")
+	// CoverageHTMLFlag is a flag that names a file to which the HTML coverage report is written
+	CoverageHTMLFlag = flag.String(
+		"coveragehtml",
+		"",
+		"set to the name of a file to which the HTML coverage report will be written")
+	// CoverageLCOVFlag is a flag that names a file to which coverage data is written in LCOV format
+	CoverageLCOVFlag = flag.String(
+		"coveragelcov",
+		"",
+		"set to the name of a file to which coverage data will be written in LCOV format")
+	// NoBrowserFlag is a flag that prevents the coverage report from being displayed in a browser; it is only needed
+	// when no other coverage output is requested
+	NoBrowserFlag = flag.Bool(
+		"nobrowser",
+		false,
+		"set to prevent the coverage report from being displayed in a browser when no other coverage output is requested")
+)
+
+// CoverageReportOptions controls how CoverageReport presents coverage data;
+// any combination of the options may be used
+type CoverageReportOptions struct {
+	// Browser displays the HTML report in the current browser window
+	Browser bool
+	// HTMLFile, if not empty, names the file to which the HTML report is
+	// written
+	HTMLFile string
+	// FuncSummary prints the coverage of each function, lowest coverage first
+	FuncSummary bool
+	// LCOVFile, if not empty, names the file to which the coverage data is
+	// written in LCOV format (see WriteLCOV)
+	LCOVFile string
+	// CoberturaFile, if not empty, names the file to which the coverage data
+	// is written in Cobertura XML format (see WriteCobertura)
+	CoberturaFile string
+}
+
+// CoverageThresholds are the minimum statement coverage percentages enforced
+// by CoverageGate; a threshold of zero is not enforced
+type CoverageThresholds struct {
+	// Total is the minimum coverage of all (non-excluded) packages combined
+	Total float64
+	// Package is the minimum coverage of each (non-excluded) package
+	Package float64
+	// PackageOverrides maps package import paths to minimum coverage values
+	// that replace Package for those packages
+	PackageOverrides map[string]float64
+	// Excluded lists packages, such as generated code, that are ignored; a
+	// pattern ending in "/..." matches the package and every package below it
+	Excluded []string
+}
+
+// coverageBlock is a single line of a coverage profile
+type coverageBlock struct {
+	file       string
+	startLine  int
+	startCol   int
+	endLine    int
+	endCol     int
+	statements int
+	count      int
+}
+
+// coverageProfile is a parsed coverage profile; blocks reported more than
+// once (as happens when several test binaries cover the same package, or when
+// profiles are merged) are combined
+type coverageProfile struct {
+	mode   string
+	blocks []coverageBlock
+	// index maps each block's file and position to its index in blocks
+	index map[string]int
+}
+
+// coverageStats counts statements and covered statements
+type coverageStats struct {
+	statements int
+	covered    int
+}
+
+func (cs coverageStats) percent() float64 {
+	if cs.statements == 0 {
+		return 100
+	}
+	return 100 * float64(cs.covered) / float64(cs.statements)
+}
+
+// FlagCoverageReportOptions returns the CoverageReportOptions specified by the
+// command line flags. The report is displayed in a browser only if no other
+// coverage output is requested and the -nobrowser flag is not set, so that
+// requesting a file or a function summary is enough for headless use
+func FlagCoverageReportOptions() CoverageReportOptions {
+	options := CoverageReportOptions{
+		HTMLFile:      *CoverageHTMLFlag,
+		FuncSummary:   *CoverageFuncFlag,
+		LCOVFile:      *CoverageLCOVFlag,
+		CoberturaFile: *CoverageCoberturaFlag,
+	}
+	options.Browser = options == CoverageReportOptions{} && !*NoBrowserFlag
+	return options
+}
+
+// CoverageReport presents the coverage data in the coverage profile as
+// directed by the options: writing the HTML report to a file, printing a
+// per-function summary, exporting LCOV and Cobertura files, and displaying the
+// HTML report in a browser. Returns false on failure
+func CoverageReport(a *goyek.A, coverageDataFile string, options CoverageReportOptions) bool {
+	if isIllegalFileName(coverageDataFile) {
+		fmt.Fprintf(os.Stderr, "cannot accept %q as a valid file name from which coverage data can be read\n", coverageDataFile)
+		return false
+	}
+	if options.HTMLFile != "" {
+		if isIllegalFileName(options.HTMLFile) {
+			fmt.Fprintf(os.Stderr, "cannot accept %q as a valid file name to which a coverage report can be written\n", options.HTMLFile)
+			return false
+		}
+		fmt.Printf("writing coverage report from %q to %q\n", coverageDataFile, options.HTMLFile)
+		if !RunCommand(a, fmt.Sprintf("go tool cover -html=%s -o %s", quoteArg(coverageDataFile), quoteArg(options.HTMLFile))) {
+			return false
+		}
+	}
+	if options.FuncSummary {
+		fmt.Printf("summarizing coverage by function from %q\n", coverageDataFile)
+		state, stdout, stderr := cmdCapture(a, directedCommand{
+			command: fmt.Sprintf("go tool cover -func=%s", quoteArg(coverageDataFile)),
+			dir:     WorkingDir(),
+		})
+		if stderr != "" {
+			printIt(stderr)
+		}
+		if !state {
+			return false
+		}
+		printIt(functionCoverageSummary(stdout))
+	}
+	if options.LCOVFile != "" && !WriteLCOV(coverageDataFile, options.LCOVFile) {
+		return false
+	}
+	if options.CoberturaFile != "" && !WriteCobertura(coverageDataFile, options.CoberturaFile) {
+		return false
+	}
+	if options.Browser {
+		fmt.Printf("displaying coverage report from %q\n", coverageDataFile)
+		return RunCommand(a, fmt.Sprintf("go tool cover -html=%s", coverageDataFile))
+	}
+	return true
+}
+
+// functionCoverage is one line of 'go tool cover -func' output
+type functionCoverage struct {
+	position string
+	function string
+	percent  float64
+}
+
+// functionCoverageSummary reformats the output of 'go tool cover -func' as a
+// table sorted by ascending coverage, keeping the total as the last line
+func functionCoverageSummary(output string) string {
+	functions := make([]functionCoverage, 0)
+	total := ""
+	for line := range strings.SplitSeq(output, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) < 3 {
+			continue
+		}
+		percent, err := strconv.ParseFloat(strings.TrimSuffix(fields[len(fields)-1], "%"), 64)
+		if err != nil {
+			continue
+		}
+		if fields[0] == "total:" {
+			total = fields[len(fields)-1]
+			continue
+		}
+		functions = append(functions, functionCoverage{position: fields[0], function: fields[1], percent: percent})
+	}
+	slices.SortStableFunc(functions, func(f1, f2 functionCoverage) int {
+		return cmp.Compare(f1.percent, f2.percent)
+	})
+	buffer := &bytes.Buffer{}
+	w := tabwriter.NewWriter(buffer, 0, 8, 2, ' ', 0)
+	fmt.Fprintln(w, "coverage\tfunction\tposition")
+	for _, f := range functions {
+		fmt.Fprintf(w, "%.1f%%\t%s\t%s\n", f.percent, f.function, f.position)
+	}
+	if total != "" {
+		fmt.Fprintf(w, "%s\ttotal\n", total)
+	}
+	_ = w.Flush()
+	return EatTrailingEOL(buffer.String())
+}
+
+// CoverageGate reads the coverage profile written by 'go test -coverprofile',
+// reports the statement coverage of each package and of all packages
+// combined, and returns false if any of them falls below its threshold
+func CoverageGate(coverageDataFile string, thresholds CoverageThresholds) bool {
+	profile, ok := readCoverageProfile(coverageDataFile)
+	if !ok {
+		return false
+	}
+	packages := profile.packageStats()
+	var total coverageStats
+	passed := true
+	lines := []string{"package\tcoverage\tminimum\tstatus"}
+	for _, pkg := range slices.Sorted(maps.Keys(packages)) {
+		if matchesAnyPackagePattern(pkg, thresholds.Excluded) {
+			continue
+		}
+		stats := packages[pkg]
+		total.statements += stats.statements
+		total.covered += stats.covered
+		minimum := thresholds.Package
+		if override, found := thresholds.PackageOverrides[pkg]; found {
+			minimum = override
+		}
+		status := coverageStatus(stats.percent(), minimum)
+		passed = passed && status != "FAIL"
+		lines = append(lines, fmt.Sprintf("%s\t%.1f%%\t%.1f%%\t%s", pkg, stats.percent(), minimum, status))
+	}
+	status := coverageStatus(total.percent(), thresholds.Total)
+	passed = passed && status != "FAIL"
+	lines = append(lines, fmt.Sprintf("total\t%.1f%%\t%.1f%%\t%s", total.percent(), thresholds.Total, status))
+	printIt(strings.Join(lines, "\n"))
+	if !passed {
+		fmt.Fprintln(os.Stderr, "code coverage is below the required minimum")
+	}
+	return passed
+}
+
+func coverageStatus(percent, minimum float64) string {
+	if percent < minimum {
+		return "FAIL"
+	}
+	return "ok"
+}
+
+// matchesAnyPackagePattern returns true if the package import path matches
+// one of the patterns; a pattern ending in "/..." matches the named package
+// and any package beneath it, and any other pattern must match exactly
+func matchesAnyPackagePattern(pkg string, patterns []string) bool {
+	for _, pattern := range patterns {
+		if prefix, found := strings.CutSuffix(pattern, "/..."); found {
+			if pkg == prefix || strings.HasPrefix(pkg, prefix+"/") {
+				return true
+			}
+		} else if pkg == pattern {
+			return true
+		}
+	}
+	return false
+}
+
+// readCoverageProfile reads and parses the named coverage profile, which is
+// located relative to WorkingDir(); returns false, after reporting the error,
+// on failure
+func readCoverageProfile(coverageDataFile string) (*coverageProfile, bool) {
+	if isIllegalFileName(coverageDataFile) {
+		fmt.Fprintf(os.Stderr, "cannot accept %q as a valid file name from which coverage data can be read\n", coverageDataFile)
+		return nil, false
+	}
+	fileName := filepath.Join(WorkingDir(), coverageDataFile)
+	content, err := afero.ReadFile(BuildFS, fileName)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "error %v reading %q\n", err, fileName)
+		return nil, false
+	}
+	profile, err := parseCoverageProfile(string(content))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "error %v parsing %q\n", err, fileName)
+		return nil, false
+	}
+	return profile, true
+}
+
+// parseCoverageProfile parses the content of a coverage profile, in which
+// each line after the 'mode:' line has the form
+// 'file:startLine.startCol,endLine.endCol statements count'
+func parseCoverageProfile(content string) (*coverageProfile, error) {
+	profile := &coverageProfile{index: map[string]int{}}
+	for lineNumber, line := range strings.Split(content, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		if mode, found := strings.CutPrefix(line, "mode:"); found {
+			if profile.mode == "" {
+				profile.mode = strings.TrimSpace(mode)
+			}
+			continue
+		}
+		block, err := parseCoverageBlock(line)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", lineNumber+1, err)
+		}
+		profile.add(block)
+	}
+	if profile.mode == "" {
+		return nil, fmt.Errorf("missing mode line")
+	}
+	return profile, nil
+}
+
+// add adds a block to the profile, combining its count with that of a block
+// already in the profile for the same file and position
+func (cp *coverageProfile) add(block coverageBlock) {
+	key := block.file + ":" + block.position()
+	if existing, found := cp.index[key]; found {
+		cp.blocks[existing].count = cp.combine(cp.blocks[existing].count, block.count)
+		return
+	}
+	cp.index[key] = len(cp.blocks)
+	cp.blocks = append(cp.blocks, block)
+}
+
+func parseCoverageBlock(line string) (coverageBlock, error) {
+	block := coverageBlock{}
+	colon := strings.LastIndex(line, ":")
+	if colon < 0 {
+		return block, fmt.Errorf("malformed coverage data %q", line)
+	}
+	block.file = line[:colon]
+	if _, err := fmt.Sscanf(line[colon+1:], "%d.%d,%d.%d %d %d",
+		&block.startLine, &block.startCol, &block.endLine, &block.endCol, &block.statements, &block.count); err != nil {
+		return block, fmt.Errorf("malformed coverage data %q: %w", line, err)
+	}
+	return block, nil
+}
+
+func (cb coverageBlock) position() string {
+	return strconv.Itoa(cb.startLine) + "." + strconv.Itoa(cb.startCol) + "," +
+		strconv.Itoa(cb.endLine) + "." + strconv.Itoa(cb.endCol)
+}
+
+// combine merges the counts of a block reported more than once
+func (cp *coverageProfile) combine(count1, count2 int) int {
+	if cp.mode == "set" {
+		return max(count1, count2)
+	}
+	return count1 + count2
+}
+
+// packageStats computes the statement coverage of each package in the
+// profile, keyed by the package import path
+func (cp *coverageProfile) packageStats() map[string]coverageStats {
+	packages := map[string]coverageStats{}
+	for _, block := range cp.blocks {
+		pkg := path.Dir(block.file)
+		stats := packages[pkg]
+		stats.statements += block.statements
+		if block.count > 0 {
+			stats.covered += block.statements
+		}
+		packages[pkg] = stats
+	}
+	return packages
+}
@@ -0,0 +1,362 @@
+package tools_build
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/goyek/goyek/v3"
+	"github.com/goyek/x/cmd"
+	"github.com/spf13/afero"
+)
+
+// Copyright © 2026 Marc Johnson (marc.johnson27591@gmail.com)
+
+const sampleCoverageProfile = "" +
+	"mode: set\n" +
+	"example.com/m/a/a.go:3.20,5.2 2 1\n" +
+	"example.com/m/a/a.go:7.20,9.2 2 0\n" +
+	"example.com/m/b/b.go:3.20,6.2 3 1\n" +
+	"example.com/m/b/b.go:3.20,6.2 3 0\n" +
+	"example.com/m/gen/gen.go:3.20,6.2 10 0\n"
+
+func TestFlagCoverageReportOptions(t *testing.T) {
+	originalCoverageFuncFlag := CoverageFuncFlag
+	originalCoverageHTMLFlag := CoverageHTMLFlag
+	originalCoverageLCOVFlag := CoverageLCOVFlag
+	originalNoBrowserFlag := NoBrowserFlag
+	defer func() {
+		CoverageFuncFlag = originalCoverageFuncFlag
+		CoverageHTMLFlag = originalCoverageHTMLFlag
+		CoverageLCOVFlag = originalCoverageLCOVFlag
+		NoBrowserFlag = originalNoBrowserFlag
+	}()
+	tests := map[string]struct {
+		coverageFunc bool
+		coverageHTML string
+		coverageLCOV string
+		noBrowser    bool
+		want         CoverageReportOptions
+	}{
+		"defaults": {
+			want: CoverageReportOptions{Browser: true},
+		},
+		"no browser": {
+			noBrowser: true,
+			want:      CoverageReportOptions{},
+		},
+		"html file": {
+			coverageHTML: "coverage.html",
+			want:         CoverageReportOptions{HTMLFile: "coverage.html"},
+		},
+		"function summary": {
+			coverageFunc: true,
+			want:         CoverageReportOptions{FuncSummary: true},
+		},
+		"lcov file": {
+			coverageLCOV: "lcov.info",
+			want:         CoverageReportOptions{LCOVFile: "lcov.info"},
+		},
+		"headless": {
+			coverageFunc: true,
+			coverageHTML: "coverage.html",
+			noBrowser:    true,
+			want:         CoverageReportOptions{HTMLFile: "coverage.html", FuncSummary: true},
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			coverageFunc := tt.coverageFunc
+			CoverageFuncFlag = &coverageFunc
+			coverageHTML := tt.coverageHTML
+			CoverageHTMLFlag = &coverageHTML
+			coverageLCOV := tt.coverageLCOV
+			CoverageLCOVFlag = &coverageLCOV
+			noBrowser := tt.noBrowser
+			NoBrowserFlag = &noBrowser
+			if got := FlagCoverageReportOptions(); got != tt.want {
+				t.Errorf("FlagCoverageReportOptions() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCoverageReport(t *testing.T) {
+	originalCachedWorkingDir := CachedWorkingDir
+	originalExecFn := ExecFn
+	originalCmdCapture := cmdCapture
+	defer func() {
+		CachedWorkingDir = originalCachedWorkingDir
+		ExecFn = originalExecFn
+		cmdCapture = originalCmdCapture
+	}()
+	CachedWorkingDir = "work"
+	tests := map[string]struct {
+		file         string
+		options      CoverageReportOptions
+		execSucceeds bool
+		wantCommands []string
+		want         bool
+	}{
+		"bad profile": {
+			file:         "/coverage.out",
+			options:      CoverageReportOptions{Browser: true},
+			execSucceeds: true,
+			wantCommands: []string{},
+			want:         false,
+		},
+		"bad html file": {
+			file:         "coverage.out",
+			options:      CoverageReportOptions{HTMLFile: "../coverage.html"},
+			execSucceeds: true,
+			wantCommands: []string{},
+			want:         false,
+		},
+		"nothing to do": {
+			file:         "coverage.out",
+			options:      CoverageReportOptions{},
+			execSucceeds: true,
+			wantCommands: []string{},
+			want:         true,
+		},
+		"html file fails": {
+			file:         "coverage.out",
+			options:      CoverageReportOptions{HTMLFile: "coverage.html", FuncSummary: true},
+			execSucceeds: false,
+			wantCommands: []string{"go tool cover -html=coverage.out -o coverage.html"},
+			want:         false,
+		},
+		"func summary fails": {
+			file:         "coverage.out",
+			options:      CoverageReportOptions{FuncSummary: true, Browser: true},
+			execSucceeds: false,
+			wantCommands: []string{"go tool cover -func=coverage.out"},
+			want:         false,
+		},
+		"everything": {
+			file:         "coverage.out",
+			options:      CoverageReportOptions{HTMLFile: "coverage.html", FuncSummary: true, Browser: true},
+			execSucceeds: true,
+			wantCommands: []string{
+				"go tool cover -html=coverage.out -o coverage.html",
+				"go tool cover -func=coverage.out",
+				"go tool cover -html=coverage.out",
+			},
+			want: true,
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			gotCommands := make([]string, 0)
+			ExecFn = func(_ *goyek.A, cmd string, _ ...cmd.Option) bool {
+				gotCommands = append(gotCommands, cmd)
+				return tt.execSucceeds
+			}
+			cmdCapture = func(_ *goyek.A, dC directedCommand) (bool, string, string) {
+				gotCommands = append(gotCommands, dC.command)
+				return tt.execSucceeds, "a/a.go:3:\tA\t\t50.0%\ntotal:\t\t\t(statements)\t50.0%", ""
+			}
+			if got := CoverageReport(nil, tt.file, tt.options); got != tt.want {
+				t.Errorf("CoverageReport() = %v, want %v", got, tt.want)
+			}
+			if !reflect.DeepEqual(gotCommands, tt.wantCommands) {
+				t.Errorf("CoverageReport() commands = %v, want %v", gotCommands, tt.wantCommands)
+			}
+		})
+	}
+}
+
+func Test_functionCoverageSummary(t *testing.T) {
+	output := "" +
+		"example.com/m/a.go:3:\tWell\t\t100.0%\n" +
+		"example.com/m/a.go:9:\tPoorly\t\t12.5%\n" +
+		"example.com/m/b.go:4:\tNever\t\t0.0%\n" +
+		"total:\t\t\t(statements)\t37.5%"
+	want := strings.Join([]string{
+		"coverage  function  position",
+		"0.0%      Never     example.com/m/b.go:4:",
+		"12.5%     Poorly    example.com/m/a.go:9:",
+		"100.0%    Well      example.com/m/a.go:3:",
+		"37.5%     total",
+	}, "\n")
+	if got := functionCoverageSummary(output); got != want {
+		t.Errorf("functionCoverageSummary() = %q, want %q", got, want)
+	}
+}
+
+func TestCoverageGate(t *testing.T) {
+	originalBuildFS := BuildFS
+	originalCachedWorkingDir := CachedWorkingDir
+	defer func() {
+		BuildFS = originalBuildFS
+		CachedWorkingDir = originalCachedWorkingDir
+	}()
+	CachedWorkingDir = "work"
+	BuildFS = afero.NewMemMapFs()
+	_ = afero.WriteFile(BuildFS, "work/coverage.out", []byte(sampleCoverageProfile), fileMode)
+	_ = afero.WriteFile(BuildFS, "work/garbage.out", []byte("mode: set\nnonsense\n"), fileMode)
+	tests := map[string]struct {
+		file       string
+		thresholds CoverageThresholds
+		want       bool
+	}{
+		"illegal file": {
+			file: "../coverage.out",
+			want: false,
+		},
+		"missing file": {
+			file: "missing.out",
+			want: false,
+		},
+		"unparseable file": {
+			file: "garbage.out",
+			want: false,
+		},
+		"no thresholds": {
+			file: "coverage.out",
+			want: true,
+		},
+		"total too low": {
+			file:       "coverage.out",
+			thresholds: CoverageThresholds{Total: 50},
+			want:       false,
+		},
+		"total ok after exclusion": {
+			file:       "coverage.out",
+			thresholds: CoverageThresholds{Total: 50, Excluded: []string{"example.com/m/gen"}},
+			want:       true,
+		},
+		"package too low": {
+			file:       "coverage.out",
+			thresholds: CoverageThresholds{Package: 60, Excluded: []string{"example.com/m/gen/..."}},
+			want:       false,
+		},
+		"package override": {
+			file: "coverage.out",
+			thresholds: CoverageThresholds{
+				Package:          60,
+				PackageOverrides: map[string]float64{"example.com/m/a": 50},
+				Excluded:         []string{"example.com/m/gen/..."},
+			},
+			want: true,
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			if got := CoverageGate(tt.file, tt.thresholds); got != tt.want {
+				t.Errorf("CoverageGate() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_matchesAnyPackagePattern(t *testing.T) {
+	tests := map[string]struct {
+		pkg      string
+		patterns []string
+		want     bool
+	}{
+		"no patterns": {
+			pkg:  "example.com/m/a",
+			want: false,
+		},
+		"exact": {
+			pkg:      "example.com/m/a",
+			patterns: []string{"example.com/m/b", "example.com/m/a"},
+			want:     true,
+		},
+		"exact does not match child": {
+			pkg:      "example.com/m/a/b",
+			patterns: []string{"example.com/m/a"},
+			want:     false,
+		},
+		"wildcard matches self": {
+			pkg:      "example.com/m/a",
+			patterns: []string{"example.com/m/a/..."},
+			want:     true,
+		},
+		"wildcard matches child": {
+			pkg:      "example.com/m/a/b",
+			patterns: []string{"example.com/m/a/..."},
+			want:     true,
+		},
+		"wildcard does not match sibling": {
+			pkg:      "example.com/m/ab",
+			patterns: []string{"example.com/m/a/..."},
+			want:     false,
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			if got := matchesAnyPackagePattern(tt.pkg, tt.patterns); got != tt.want {
+				t.Errorf("matchesAnyPackagePattern() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_parseCoverageProfile(t *testing.T) {
+	tests := map[string]struct {
+		content    string
+		wantMode   string
+		wantBlocks []coverageBlock
+		wantErr    bool
+	}{
+		"empty": {
+			content: "",
+			wantErr: true,
+		},
+		"malformed": {
+			content: "mode: set\nexample.com/m/a.go:nonsense\n",
+			wantErr: true,
+		},
+		"no colon": {
+			content: "mode: set\nnonsense\n",
+			wantErr: true,
+		},
+		"set mode duplicates": {
+			content:  "mode: set\na/a.go:1.2,3.4 5 1\na/a.go:1.2,3.4 5 1\n",
+			wantMode: "set",
+			wantBlocks: []coverageBlock{
+				{file: "a/a.go", startLine: 1, startCol: 2, endLine: 3, endCol: 4, statements: 5, count: 1},
+			},
+		},
+		"count mode duplicates": {
+			content:  "mode: count\na/a.go:1.2,3.4 5 1\na/a.go:1.2,3.4 5 6\na/a.go:4.1,5.2 1 0\n",
+			wantMode: "count",
+			wantBlocks: []coverageBlock{
+				{file: "a/a.go", startLine: 1, startCol: 2, endLine: 3, endCol: 4, statements: 5, count: 7},
+				{file: "a/a.go", startLine: 4, startCol: 1, endLine: 5, endCol: 2, statements: 1, count: 0},
+			},
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, err := parseCoverageProfile(tt.content)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseCoverageProfile() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			if got.mode != tt.wantMode {
+				t.Errorf("parseCoverageProfile() mode = %q, want %q", got.mode, tt.wantMode)
+			}
+			if !reflect.DeepEqual(got.blocks, tt.wantBlocks) {
+				t.Errorf("parseCoverageProfile() blocks = %v, want %v", got.blocks, tt.wantBlocks)
+			}
+		})
+	}
+}
+
+func Test_coverageProfile_packageStats(t *testing.T) {
+	profile, _ := parseCoverageProfile(sampleCoverageProfile)
+	want := map[string]coverageStats{
+		"example.com/m/a":   {statements: 4, covered: 2},
+		"example.com/m/b":   {statements: 3, covered: 3},
+		"example.com/m/gen": {statements: 10, covered: 0},
+	}
+	if got := profile.packageStats(); !reflect.DeepEqual(got, want) {
+		t.Errorf("packageStats() = %v, want %v", got, want)
+	}
+}
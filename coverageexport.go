@@ -0,0 +1,206 @@
+package tools_build
+
+import (
+	"encoding/xml"
+	"fmt"
+	"maps"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+// Copyright © 2026 Marc Johnson (marc.johnson27591@gmail.com)
+
+// lineHits maps line numbers to execution counts
+type lineHits map[int]int
+
+// lineHits computes, for each file in the profile (keyed by its import path),
+// the execution count of each line containing statements; a line covered by
+// more than one block gets the highest of their counts
+func (cp *coverageProfile) lineHits() map[string]lineHits {
+	files := map[string]lineHits{}
+	for _, block := range cp.blocks {
+		if block.statements == 0 {
+			continue
+		}
+		hits, found := files[block.file]
+		if !found {
+			hits = lineHits{}
+			files[block.file] = hits
+		}
+		for line := block.startLine; line <= block.endLine; line++ {
+			if count, seen := hits[line]; !seen || block.count > count {
+				hits[line] = block.count
+			}
+		}
+	}
+	return files
+}
+
+// covered returns the number of lines that were executed
+func (lh lineHits) covered() int {
+	count := 0
+	for _, hits := range lh {
+		if hits > 0 {
+			count++
+		}
+	}
+	return count
+}
+
+// WriteLCOV converts the coverage profile into LCOV tracefile format, writing
+// it to lcovFile; source files are named relative to WorkingDir(), using the
+// module paths declared in the go.mod files. Returns false on failure
+func WriteLCOV(coverageDataFile, lcovFile string) bool {
+	if isIllegalFileName(lcovFile) {
+		fmt.Fprintf(os.Stderr, "cannot accept %q as a valid file name to which LCOV data can be written\n", lcovFile)
+		return false
+	}
+	profile, ok := readCoverageProfile(coverageDataFile)
+	if !ok {
+		return false
+	}
+	modules, ok := findModules()
+	if !ok {
+		return false
+	}
+	fmt.Printf("writing LCOV data from %q to %q\n", coverageDataFile, lcovFile)
+	return writeWorkingFile(lcovFile, []byte(lcovReport(profile, modules)))
+}
+
+func lcovReport(profile *coverageProfile, modules []goModule) string {
+	builder := &strings.Builder{}
+	files := profile.lineHits()
+	for _, file := range slices.Sorted(maps.Keys(files)) {
+		hits := files[file]
+		builder.WriteString("TN:\n")
+		fmt.Fprintf(builder, "SF:%s\n", repoRelativePath(file, modules))
+		for _, line := range slices.Sorted(maps.Keys(hits)) {
+			fmt.Fprintf(builder, "DA:%d,%d\n", line, hits[line])
+		}
+		fmt.Fprintf(builder, "LF:%d\n", len(hits))
+		fmt.Fprintf(builder, "LH:%d\n", hits.covered())
+		builder.WriteString("end_of_record\n")
+	}
+	return builder.String()
+}
+
+type coberturaCoverage struct {
+	XMLName         xml.Name           `xml:"coverage"`
+	LineRate        string             `xml:"line-rate,attr"`
+	BranchRate      string             `xml:"branch-rate,attr"`
+	LinesCovered    int                `xml:"lines-covered,attr"`
+	LinesValid      int                `xml:"lines-valid,attr"`
+	BranchesCovered int                `xml:"branches-covered,attr"`
+	BranchesValid   int                `xml:"branches-valid,attr"`
+	Complexity      string             `xml:"complexity,attr"`
+	Version         string             `xml:"version,attr"`
+	Timestamp       int64              `xml:"timestamp,attr"`
+	Sources         []string           `xml:"sources>source"`
+	Packages        []coberturaPackage `xml:"packages>package"`
+}
+
+type coberturaPackage struct {
+	Name       string           `xml:"name,attr"`
+	LineRate   string           `xml:"line-rate,attr"`
+	BranchRate string           `xml:"branch-rate,attr"`
+	Complexity string           `xml:"complexity,attr"`
+	Classes    []coberturaClass `xml:"classes>class"`
+}
+
+type coberturaClass struct {
+	Name       string          `xml:"name,attr"`
+	FileName   string          `xml:"filename,attr"`
+	LineRate   string          `xml:"line-rate,attr"`
+	BranchRate string          `xml:"branch-rate,attr"`
+	Complexity string          `xml:"complexity,attr"`
+	Methods    struct{}        `xml:"methods"`
+	Lines      []coberturaLine `xml:"lines>line"`
+}
+
+type coberturaLine struct {
+	Number int `xml:"number,attr"`
+	Hits   int `xml:"hits,attr"`
+}
+
+func coberturaRate(covered, valid int) string {
+	if valid == 0 {
+		return "1"
+	}
+	return fmt.Sprintf("%.4f", float64(covered)/float64(valid))
+}
+
+// WriteCobertura converts the coverage profile into Cobertura XML format,
+// writing it to coberturaFile; source files are named relative to
+// WorkingDir(), using the module paths declared in the go.mod files. Returns
+// false on failure
+func WriteCobertura(coverageDataFile, coberturaFile string) bool {
+	if isIllegalFileName(coberturaFile) {
+		fmt.Fprintf(os.Stderr, "cannot accept %q as a valid file name to which Cobertura data can be written\n", coberturaFile)
+		return false
+	}
+	profile, ok := readCoverageProfile(coverageDataFile)
+	if !ok {
+		return false
+	}
+	modules, ok := findModules()
+	if !ok {
+		return false
+	}
+	source, err := filepath.Abs(WorkingDir())
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "error %v resolving %q\n", err, WorkingDir())
+		return false
+	}
+	content, err := xml.MarshalIndent(coberturaReport(profile, modules, filepath.ToSlash(source)), "", "  ")
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "error %v creating Cobertura report\n", err)
+		return false
+	}
+	fmt.Printf("writing Cobertura data from %q to %q\n", coverageDataFile, coberturaFile)
+	return writeWorkingFile(coberturaFile, append([]byte(xml.Header), append(content, '\n')...))
+}
+
+func coberturaReport(profile *coverageProfile, modules []goModule, source string) coberturaCoverage {
+	report := coberturaCoverage{
+		BranchRate: "0",
+		Complexity: "0",
+		Timestamp:  NowFn().UnixMilli(),
+		Sources:    []string{source},
+	}
+	files := profile.lineHits()
+	packages := map[string][]string{}
+	for file := range files {
+		pkg := path.Dir(file)
+		packages[pkg] = append(packages[pkg], file)
+	}
+	for _, pkgName := range slices.Sorted(maps.Keys(packages)) {
+		pkg := coberturaPackage{Name: pkgName, BranchRate: "0", Complexity: "0"}
+		var pkgCovered, pkgValid int
+		slices.Sort(packages[pkgName])
+		for _, file := range packages[pkgName] {
+			hits := files[file]
+			class := coberturaClass{
+				Name:       path.Base(file),
+				FileName:   repoRelativePath(file, modules),
+				LineRate:   coberturaRate(hits.covered(), len(hits)),
+				BranchRate: "0",
+				Complexity: "0",
+			}
+			for _, line := range slices.Sorted(maps.Keys(hits)) {
+				class.Lines = append(class.Lines, coberturaLine{Number: line, Hits: hits[line]})
+			}
+			pkgCovered += hits.covered()
+			pkgValid += len(hits)
+			pkg.Classes = append(pkg.Classes, class)
+		}
+		pkg.LineRate = coberturaRate(pkgCovered, pkgValid)
+		report.LinesCovered += pkgCovered
+		report.LinesValid += pkgValid
+		report.Packages = append(report.Packages, pkg)
+	}
+	report.LineRate = coberturaRate(report.LinesCovered, report.LinesValid)
+	return report
+}
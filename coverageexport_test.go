@@ -0,0 +1,154 @@
+package tools_build
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/spf13/afero"
+)
+
+// Copyright © 2026 Marc Johnson (marc.johnson27591@gmail.com)
+
+const exportCoverageProfile = "" +
+	"mode: count\n" +
+	"example.com/m/a/a.go:3.20,5.2 2 4\n" +
+	"example.com/m/a/a.go:5.2,7.3 1 0\n" +
+	"example.com/m/a/a.go:9.1,9.10 0 0\n" +
+	"example.com/m/b/b.go:1.1,2.2 1 0\n"
+
+func Test_coverageProfile_lineHits(t *testing.T) {
+	profile, _ := parseCoverageProfile(exportCoverageProfile)
+	want := map[string]lineHits{
+		"example.com/m/a/a.go": {3: 4, 4: 4, 5: 4, 6: 0, 7: 0},
+		"example.com/m/b/b.go": {1: 0, 2: 0},
+	}
+	if got := profile.lineHits(); !reflect.DeepEqual(got, want) {
+		t.Errorf("lineHits() = %v, want %v", got, want)
+	}
+}
+
+func setUpCoverageExport(t *testing.T) {
+	t.Helper()
+	originalBuildFS := BuildFS
+	originalCachedWorkingDir := CachedWorkingDir
+	originalNowFn := NowFn
+	t.Cleanup(func() {
+		BuildFS = originalBuildFS
+		CachedWorkingDir = originalCachedWorkingDir
+		NowFn = originalNowFn
+	})
+	CachedWorkingDir = "work"
+	NowFn = func() time.Time { return time.UnixMilli(1234) }
+	BuildFS = afero.NewMemMapFs()
+	_ = BuildFS.MkdirAll("work/tools", dirMode)
+	_ = afero.WriteFile(BuildFS, "work/go.mod", []byte("module example.com/m\n"), fileMode)
+	_ = afero.WriteFile(BuildFS, "work/tools/go.mod", []byte("module example.com/m/tools\n"), fileMode)
+	_ = afero.WriteFile(BuildFS, "work/coverage.out", []byte(exportCoverageProfile), fileMode)
+}
+
+func TestWriteLCOV(t *testing.T) {
+	setUpCoverageExport(t)
+	tests := map[string]struct {
+		profile     string
+		lcovFile    string
+		wantContent string
+		want        bool
+	}{
+		"bad lcov file": {
+			profile:  "coverage.out",
+			lcovFile: "../lcov.info",
+			want:     false,
+		},
+		"missing profile": {
+			profile:  "missing.out",
+			lcovFile: "lcov.info",
+			want:     false,
+		},
+		"success": {
+			profile:  "coverage.out",
+			lcovFile: "lcov.info",
+			wantContent: strings.Join([]string{
+				"TN:",
+				"SF:a/a.go",
+				"DA:3,4",
+				"DA:4,4",
+				"DA:5,4",
+				"DA:6,0",
+				"DA:7,0",
+				"LF:5",
+				"LH:3",
+				"end_of_record",
+				"TN:",
+				"SF:b/b.go",
+				"DA:1,0",
+				"DA:2,0",
+				"LF:2",
+				"LH:0",
+				"end_of_record",
+				"",
+			}, "\n"),
+			want: true,
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			if got := WriteLCOV(tt.profile, tt.lcovFile); got != tt.want {
+				t.Errorf("WriteLCOV() = %v, want %v", got, tt.want)
+			}
+			if tt.want {
+				content, _ := afero.ReadFile(BuildFS, "work/"+tt.lcovFile)
+				if string(content) != tt.wantContent {
+					t.Errorf("WriteLCOV() content = %q, want %q", content, tt.wantContent)
+				}
+			}
+		})
+	}
+}
+
+func TestWriteCobertura(t *testing.T) {
+	setUpCoverageExport(t)
+	tests := map[string]struct {
+		profile       string
+		coberturaFile string
+		wantContent   []string
+		want          bool
+	}{
+		"bad cobertura file": {
+			profile:       "coverage.out",
+			coberturaFile: "/cobertura.xml",
+			want:          false,
+		},
+		"missing profile": {
+			profile:       "missing.out",
+			coberturaFile: "cobertura.xml",
+			want:          false,
+		},
+		"success": {
+			profile:       "coverage.out",
+			coberturaFile: "cobertura.xml",
+			wantContent: []string{
+				`<coverage line-rate="0.4286" branch-rate="0" lines-covered="3" lines-valid="7" branches-covered="0" branches-valid="0" complexity="0" version="" timestamp="1234">`,
+				`<package name="example.com/m/a" line-rate="0.6000" branch-rate="0" complexity="0">`,
+				`<class name="a.go" filename="a/a.go" line-rate="0.6000" branch-rate="0" complexity="0">`,
+				`<line number="3" hits="4"></line>`,
+				`<package name="example.com/m/b" line-rate="0.0000" branch-rate="0" complexity="0">`,
+			},
+			want: true,
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			if got := WriteCobertura(tt.profile, tt.coberturaFile); got != tt.want {
+				t.Errorf("WriteCobertura() = %v, want %v", got, tt.want)
+			}
+			content, _ := afero.ReadFile(BuildFS, "work/"+tt.coberturaFile)
+			for _, want := range tt.wantContent {
+				if !strings.Contains(string(content), want) {
+					t.Errorf("WriteCobertura() content missing %q: %s", want, content)
+				}
+			}
+		})
+	}
+}
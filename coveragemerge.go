@@ -0,0 +1,103 @@
+package tools_build
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/goyek/goyek/v3"
+)
+
+// Copyright © 2026 Marc Johnson (marc.johnson27591@gmail.com)
+
+// MergedCoverage runs the unit tests in every module found in the working
+// directory tree, converts the coverage data in the coverDirs (directories,
+// relative to WorkingDir(), written by binaries built with 'go build -cover'
+// and run with GOCOVERDIR set), and merges all of it into a single coverage
+// profile, coverageDataFile, suitable for CoverageReport, CoverageGate, and
+// the other coverage helpers. Returns false on failure
+func MergedCoverage(a *goyek.A, coverageDataFile string, coverDirs []string) bool {
+	if isIllegalFileName(coverageDataFile) {
+		fmt.Fprintf(os.Stderr, "cannot accept %q as a valid file name to which coverage data can be written\n", coverageDataFile)
+		return false
+	}
+	for _, dir := range coverDirs {
+		if isIllegalFileName(dir) {
+			fmt.Fprintf(os.Stderr, "cannot accept %q as a valid coverage data directory\n", dir)
+			return false
+		}
+	}
+	dirs, err := RelevantDirs(matchModuleFile)
+	if err != nil {
+		return false
+	}
+	partialFiles := make([]string, 0, len(dirs)+1)
+	defer func() {
+		removePartialProfiles(partialFiles)
+	}()
+	for index, dir := range dirs {
+		partialFile := fmt.Sprintf("%s.%d", coverageDataFile, index)
+		partialFiles = append(partialFiles, partialFile)
+		path := filepath.Join(WorkingDir(), dir)
+		relativeFile, _ := filepath.Rel(path, filepath.Join(WorkingDir(), partialFile))
+		fmt.Printf("%q: executing unit tests, collecting coverage data\n", path)
+		testCommand := directedCommand{
+			command: fmt.Sprintf("go test -coverprofile=%s ./...", quoteArg(filepath.ToSlash(relativeFile))),
+			dir:     path,
+		}
+		if !testCommand.execute(a) {
+			return false
+		}
+	}
+	if len(coverDirs) != 0 {
+		partialFile := fmt.Sprintf("%s.%d", coverageDataFile, len(dirs))
+		partialFiles = append(partialFiles, partialFile)
+		fmt.Printf("converting coverage data from %s\n", strings.Join(coverDirs, ", "))
+		command := fmt.Sprintf("go tool covdata textfmt -i=%s -o=%s",
+			quoteArg(strings.Join(coverDirs, ",")), quoteArg(partialFile))
+		if !RunCommand(a, command) {
+			return false
+		}
+	}
+	merged := &coverageProfile{index: map[string]int{}}
+	for _, partialFile := range partialFiles {
+		profile, ok := readCoverageProfile(partialFile)
+		if !ok {
+			return false
+		}
+		merged.merge(profile)
+	}
+	if merged.mode == "" {
+		merged.mode = "set"
+	}
+	fmt.Printf("writing merged coverage data to %q\n", coverageDataFile)
+	return writeWorkingFile(coverageDataFile, []byte(merged.String()))
+}
+
+// merge adds the blocks of another profile; if the profiles' modes differ,
+// the merged profile takes the counting mode, so that no counts are lost
+func (cp *coverageProfile) merge(other *coverageProfile) {
+	if cp.mode == "" || cp.mode == "set" {
+		cp.mode = other.mode
+	}
+	for _, block := range other.blocks {
+		cp.add(block)
+	}
+}
+
+// String renders the profile in the format written by 'go test -coverprofile'
+func (cp *coverageProfile) String() string {
+	builder := &strings.Builder{}
+	fmt.Fprintf(builder, "mode: %s\n", cp.mode)
+	for _, block := range cp.blocks {
+		fmt.Fprintf(builder, "%s:%s %d %d\n", block.file, block.position(), block.statements, block.count)
+	}
+	return builder.String()
+}
+
+func removePartialProfiles(partialFiles []string) {
+	for _, partialFile := range partialFiles {
+		_ = BuildFS.Remove(filepath.Join(WorkingDir(), partialFile))
+	}
+}
@@ -0,0 +1,126 @@
+package tools_build
+
+import (
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/goyek/goyek/v3"
+	"github.com/goyek/x/cmd"
+	"github.com/spf13/afero"
+)
+
+// Copyright © 2026 Marc Johnson (marc.johnson27591@gmail.com)
+
+func TestMergedCoverage(t *testing.T) {
+	originalBuildFS := BuildFS
+	originalCachedWorkingDir := CachedWorkingDir
+	originalExecFn := ExecFn
+	defer func() {
+		BuildFS = originalBuildFS
+		CachedWorkingDir = originalCachedWorkingDir
+		ExecFn = originalExecFn
+	}()
+	CachedWorkingDir = "work"
+	partialProfiles := map[string]string{
+		"work/coverage.out.0": "mode: set\nexample.com/m/a.go:1.1,2.2 1 1\nexample.com/m/a.go:3.1,4.2 1 0\n",
+		"work/coverage.out.1": "mode: set\nexample.com/m/tools/t.go:1.1,2.2 2 0\n",
+		"work/coverage.out.2": "mode: count\nexample.com/m/a.go:3.1,4.2 1 5\nexample.com/m/tools/t.go:1.1,2.2 2 2\n",
+	}
+	tests := map[string]struct {
+		file         string
+		coverDirs    []string
+		failCommand  string
+		wantCommands []string
+		wantContent  string
+		want         bool
+	}{
+		"bad file": {
+			file:         "../coverage.out",
+			wantCommands: []string{},
+			want:         false,
+		},
+		"bad cover dir": {
+			file:         "coverage.out",
+			coverDirs:    []string{"/tmp/cover"},
+			wantCommands: []string{},
+			want:         false,
+		},
+		"tests fail": {
+			file:         "coverage.out",
+			failCommand:  "go test -coverprofile=coverage.out.0 ./...",
+			wantCommands: []string{"go test -coverprofile=coverage.out.0 ./..."},
+			want:         false,
+		},
+		"modules only": {
+			file: "coverage.out",
+			wantCommands: []string{
+				"go test -coverprofile=coverage.out.0 ./...",
+				"go test -coverprofile=../coverage.out.1 ./...",
+			},
+			wantContent: "mode: set\n" +
+				"example.com/m/a.go:1.1,2.2 1 1\n" +
+				"example.com/m/a.go:3.1,4.2 1 0\n" +
+				"example.com/m/tools/t.go:1.1,2.2 2 0\n",
+			want: true,
+		},
+		"modules and cover dirs": {
+			file:      "coverage.out",
+			coverDirs: []string{"e2e/cover1", "e2e/cover2"},
+			wantCommands: []string{
+				"go test -coverprofile=coverage.out.0 ./...",
+				"go test -coverprofile=../coverage.out.1 ./...",
+				"go tool covdata textfmt -i=e2e/cover1,e2e/cover2 -o=coverage.out.2",
+			},
+			wantContent: "mode: count\n" +
+				"example.com/m/a.go:1.1,2.2 1 1\n" +
+				"example.com/m/a.go:3.1,4.2 1 5\n" +
+				"example.com/m/tools/t.go:1.1,2.2 2 2\n",
+			want: true,
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			BuildFS = afero.NewMemMapFs()
+			_ = BuildFS.MkdirAll("work/tools", dirMode)
+			_ = afero.WriteFile(BuildFS, "work/go.mod", []byte("module example.com/m\n"), fileMode)
+			_ = afero.WriteFile(BuildFS, "work/tools/go.mod", []byte("module example.com/m/tools\n"), fileMode)
+			gotCommands := make([]string, 0)
+			ExecFn = func(_ *goyek.A, cmd string, _ ...cmd.Option) bool {
+				gotCommands = append(gotCommands, cmd)
+				if cmd == tt.failCommand {
+					return false
+				}
+				// simulate the partial profile being written
+				fields := strings.Fields(cmd)
+				partial := filepath.ToSlash(filepath.Join("work", strings.TrimPrefix(fields[len(fields)-1], "-o=")))
+				if strings.HasPrefix(cmd, "go test") {
+					partial = filepath.ToSlash(filepath.Join("work", "tools", strings.TrimPrefix(fields[2], "-coverprofile=")))
+					if !strings.HasPrefix(fields[2], "-coverprofile=../") {
+						partial = filepath.ToSlash(filepath.Join("work", strings.TrimPrefix(fields[2], "-coverprofile=")))
+					}
+				}
+				_ = afero.WriteFile(BuildFS, partial, []byte(partialProfiles[partial]), fileMode)
+				return true
+			}
+			if got := MergedCoverage(nil, tt.file, tt.coverDirs); got != tt.want {
+				t.Errorf("MergedCoverage() = %v, want %v", got, tt.want)
+			}
+			if !reflect.DeepEqual(gotCommands, tt.wantCommands) {
+				t.Errorf("MergedCoverage() commands = %v, want %v", gotCommands, tt.wantCommands)
+			}
+			if tt.want {
+				content, _ := afero.ReadFile(BuildFS, "work/coverage.out")
+				if string(content) != tt.wantContent {
+					t.Errorf("MergedCoverage() content = %q, want %q", content, tt.wantContent)
+				}
+			}
+			for partial := range partialProfiles {
+				if exists, _ := afero.Exists(BuildFS, partial); exists {
+					t.Errorf("MergedCoverage() did not remove %q", partial)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,199 @@
+package tools_build
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+
+	"github.com/goyek/goyek/v3"
+	"github.com/spf13/afero"
+)
+
+// Copyright © 2026 Marc Johnson (marc.johnson27591@gmail.com)
+
+// DefaultDeadcodeAllowlistFile is the deadcode allowlist file used when no
+// other file is named
+const DefaultDeadcodeAllowlistFile = "deadcode-allowlist.json"
+
+var (
+	// DeadcodeAllowlistFlag is a flag that names the deadcode allowlist file
+	DeadcodeAllowlistFlag = flag.String(
+		"deadcodeallowlist",
+		DefaultDeadcodeAllowlistFile,
+		"set to the name of the file listing unreachable functions that are not to fail the build")
+	// DeadcodeFailFlag is a flag that makes dead code analysis fail when it finds unreachable functions
+	DeadcodeFailFlag = flag.Bool(
+		"deadcodefail",
+		false,
+		"set to fail dead code analysis when it finds unreachable functions that are not allowlisted")
+)
+
+// DeadcodeAllowance excuses unreachable functions, such as exported API
+// functions that the module itself does not call. An allowlist file is a JSON
+// array of allowances, such as
+//
+//	[
+//	  {
+//	    "package": "example.com/m/api/...",
+//	    "function": "New*",
+//	    "justification": "constructors for library users"
+//	  }
+//	]
+type DeadcodeAllowance struct {
+	// Package is a package path, or a package path followed by "/...", such as
+	// "example.com/m/api/..."
+	Package string `json:"package"`
+	// Function is a pattern, in path.Match syntax, matched against function
+	// names as reported by deadcode, such as "New*", "T.Method" or
+	// "(\*T).Method"
+	Function string `json:"function"`
+	// Justification explains why the functions are allowed to be unreachable
+	Justification string `json:"justification,omitempty"`
+}
+
+// allows returns true if the allowance covers the named function in the
+// specified package
+func (da DeadcodeAllowance) allows(pkg, function string) bool {
+	if !matchesAnyPackagePattern(pkg, []string{da.Package}) {
+		return false
+	}
+	matched, _ := path.Match(da.Function, function)
+	return matched
+}
+
+// validate reports an allowance that lacks a package or has an invalid
+// function pattern
+func (da DeadcodeAllowance) validate(fileName string) bool {
+	if da.Package == "" {
+		fmt.Fprintf(os.Stderr, "%q: an allowance has no package\n", fileName)
+		return false
+	}
+	if _, err := path.Match(da.Function, ""); da.Function == "" || err != nil {
+		fmt.Fprintf(os.Stderr, "%q: the allowance for %s has an invalid function pattern %q\n", fileName, da.Package,
+			da.Function)
+		return false
+	}
+	return true
+}
+
+// DeadcodeOptions controls how Deadcode judges unreachable functions
+type DeadcodeOptions struct {
+	// Fail, if true, makes Deadcode fail when it finds unreachable functions
+	// that are not allowlisted
+	Fail bool
+	// AllowlistFile names the allowlist file, relative to WorkingDir(); empty
+	// means DefaultDeadcodeAllowlistFile
+	AllowlistFile string
+}
+
+// FlagDeadcodeOptions returns the DeadcodeOptions specified by the command
+// line flags
+func FlagDeadcodeOptions() DeadcodeOptions {
+	return DeadcodeOptions{Fail: *DeadcodeFailFlag, AllowlistFile: *DeadcodeAllowlistFlag}
+}
+
+func (do DeadcodeOptions) allowlistFile() string {
+	if do.AllowlistFile == "" {
+		return DefaultDeadcodeAllowlistFile
+	}
+	return do.AllowlistFile
+}
+
+// readDeadcodeAllowlist reads the named allowlist file, which is located
+// relative to WorkingDir(); a missing file means that no function is
+// allowlisted. Returns false, after reporting the error, if the file cannot be
+// read or parsed, or contains an invalid allowance, which is omitted
+func readDeadcodeAllowlist(allowlistFile string) ([]DeadcodeAllowance, bool) {
+	if isIllegalFileName(allowlistFile) {
+		fmt.Fprintf(os.Stderr, "cannot accept %q as a valid file name from which the deadcode allowlist can be read\n",
+			allowlistFile)
+		return nil, false
+	}
+	fileName := filepath.Join(WorkingDir(), allowlistFile)
+	if exists, _ := afero.Exists(BuildFS, fileName); !exists {
+		return []DeadcodeAllowance{}, true
+	}
+	content, err := afero.ReadFile(BuildFS, fileName)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "error %v reading %q\n", err, fileName)
+		return nil, false
+	}
+	var entries []DeadcodeAllowance
+	if err = json.Unmarshal(content, &entries); err != nil {
+		fmt.Fprintf(os.Stderr, "error %v parsing %q\n", err, fileName)
+		return nil, false
+	}
+	allowlist := make([]DeadcodeAllowance, 0, len(entries))
+	valid := true
+	for _, allowance := range entries {
+		if !allowance.validate(allowlistFile) {
+			valid = false
+			continue
+		}
+		allowlist = append(allowlist, allowance)
+	}
+	return allowlist, valid
+}
+
+// applyDeadcodeAllowlist removes the allowlisted functions from the packages,
+// returning the remaining unreachable functions and the number of functions
+// removed
+func applyDeadcodeAllowlist(packages []deadcodePackage, allowlist []DeadcodeAllowance) ([]deadcodePackage, int) {
+	remaining := make([]deadcodePackage, 0, len(packages))
+	allowed := 0
+	for _, pkg := range packages {
+		functions := make([]deadcodeFunction, 0, len(pkg.Funcs))
+		for _, function := range pkg.Funcs {
+			if deadcodeAllowed(allowlist, pkg.Path, function.Name) {
+				allowed++
+				continue
+			}
+			functions = append(functions, function)
+		}
+		if len(functions) != 0 {
+			pkg.Funcs = functions
+			remaining = append(remaining, pkg)
+		}
+	}
+	return remaining, allowed
+}
+
+func deadcodeAllowed(allowlist []DeadcodeAllowance, pkg, function string) bool {
+	for _, allowance := range allowlist {
+		if allowance.allows(pkg, function) {
+			return true
+		}
+	}
+	return false
+}
+
+// DeadcodeWithOptions runs dead code analysis (see DeadcodeFindings) and
+// reports the unreachable functions that are not in the allowlist file (see
+// DeadcodeAllowance and reportFindings); returns false on failure, or if
+// options.Fail is true and any unreachable function is not allowlisted
+func DeadcodeWithOptions(a *goyek.A, options DeadcodeOptions) bool {
+	allowlist, ok := readDeadcodeAllowlist(options.allowlistFile())
+	if !ok {
+		return false
+	}
+	packages, ok := runDeadcode(a)
+	if !ok {
+		return false
+	}
+	packages, allowed := applyDeadcodeAllowlist(packages, allowlist)
+	if allowed != 0 {
+		fmt.Printf("%d unreachable functions are allowlisted\n", allowed)
+	}
+	findings := deadcodeFindings(packages)
+	if !reportFindings("deadcode", findings) {
+		return false
+	}
+	if options.Fail && len(findings) != 0 {
+		fmt.Fprintf(os.Stderr, "%d unreachable functions found\n", len(findings))
+		return false
+	}
+	return true
+}
@@ -0,0 +1,216 @@
+package tools_build
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/goyek/goyek/v3"
+	"github.com/goyek/x/cmd"
+	"github.com/spf13/afero"
+)
+
+// Copyright © 2026 Marc Johnson (marc.johnson27591@gmail.com)
+
+func Test_readDeadcodeAllowlist(t *testing.T) {
+	originalBuildFS := BuildFS
+	originalCachedWorkingDir := CachedWorkingDir
+	defer func() {
+		BuildFS = originalBuildFS
+		CachedWorkingDir = originalCachedWorkingDir
+	}()
+	CachedWorkingDir = "work"
+	tests := map[string]struct {
+		fileName string
+		content  string
+		want     []DeadcodeAllowance
+		wantOk   bool
+	}{
+		"bad file name": {
+			fileName: "../allowlist.json",
+			want:     nil,
+			wantOk:   false,
+		},
+		"missing file": {
+			fileName: "allowlist.json",
+			want:     []DeadcodeAllowance{},
+			wantOk:   true,
+		},
+		"unparseable": {
+			fileName: "allowlist.json",
+			content:  "[",
+			want:     nil,
+			wantOk:   false,
+		},
+		"invalid entries": {
+			fileName: "allowlist.json",
+			content: `[
+				{"function": "New*"},
+				{"package": "example.com/m"},
+				{"package": "example.com/m", "function": "[New"},
+				{"package": "example.com/m/api/...", "function": "New*", "justification": "constructors"}
+			]`,
+			want: []DeadcodeAllowance{
+				{Package: "example.com/m/api/...", Function: "New*", Justification: "constructors"},
+			},
+			wantOk: false,
+		},
+		"valid": {
+			fileName: "allowlist.json",
+			content:  `[{"package": "example.com/m", "function": "(*T).String"}]`,
+			want:     []DeadcodeAllowance{{Package: "example.com/m", Function: "(*T).String"}},
+			wantOk:   true,
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			BuildFS = afero.NewMemMapFs()
+			_ = BuildFS.MkdirAll("work", dirMode)
+			if tt.content != "" {
+				_ = afero.WriteFile(BuildFS, "work/"+tt.fileName, []byte(tt.content), fileMode)
+			}
+			got, gotOk := readDeadcodeAllowlist(tt.fileName)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("readDeadcodeAllowlist() got = %v, want %v", got, tt.want)
+			}
+			if gotOk != tt.wantOk {
+				t.Errorf("readDeadcodeAllowlist() gotOk = %v, want %v", gotOk, tt.wantOk)
+			}
+		})
+	}
+}
+
+func Test_applyDeadcodeAllowlist(t *testing.T) {
+	newFunction := deadcodeFunction{Name: "NewT"}
+	stringFunction := deadcodeFunction{Name: "(*T).String"}
+	helperFunction := deadcodeFunction{Name: "helper"}
+	packages := []deadcodePackage{
+		{Path: "example.com/m/api", Funcs: []deadcodeFunction{newFunction, helperFunction}},
+		{Path: "example.com/m/api/v2", Funcs: []deadcodeFunction{newFunction}},
+		{Path: "example.com/m", Funcs: []deadcodeFunction{stringFunction}},
+	}
+	tests := map[string]struct {
+		allowlist   []DeadcodeAllowance
+		want        []deadcodePackage
+		wantAllowed int
+	}{
+		"empty allowlist": {
+			allowlist:   []DeadcodeAllowance{},
+			want:        packages,
+			wantAllowed: 0,
+		},
+		"package tree": {
+			allowlist: []DeadcodeAllowance{{Package: "example.com/m/api/...", Function: "New*"}},
+			want: []deadcodePackage{
+				{Path: "example.com/m/api", Funcs: []deadcodeFunction{helperFunction}},
+				{Path: "example.com/m", Funcs: []deadcodeFunction{stringFunction}},
+			},
+			wantAllowed: 2,
+		},
+		"single package": {
+			allowlist: []DeadcodeAllowance{
+				{Package: "example.com/m/api", Function: "*"},
+				{Package: "example.com/m", Function: "(\\*T).*"},
+			},
+			want: []deadcodePackage{
+				{Path: "example.com/m/api/v2", Funcs: []deadcodeFunction{newFunction}},
+			},
+			wantAllowed: 3,
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, gotAllowed := applyDeadcodeAllowlist(packages, tt.allowlist)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("applyDeadcodeAllowlist() got = %v, want %v", got, tt.want)
+			}
+			if gotAllowed != tt.wantAllowed {
+				t.Errorf("applyDeadcodeAllowlist() gotAllowed = %d, want %d", gotAllowed, tt.wantAllowed)
+			}
+		})
+	}
+}
+
+func TestDeadcodeWithOptions(t *testing.T) {
+	originalBuildFS := BuildFS
+	originalCachedWorkingDir := CachedWorkingDir
+	originalCmdCapture := cmdCapture
+	originalExecFn := ExecFn
+	originalNoTestFlag := NoTestFlag
+	defer func() {
+		BuildFS = originalBuildFS
+		CachedWorkingDir = originalCachedWorkingDir
+		cmdCapture = originalCmdCapture
+		ExecFn = originalExecFn
+		NoTestFlag = originalNoTestFlag
+		delete(reportedFindings, "deadcode")
+	}()
+	CachedWorkingDir = "work"
+	noTest := false
+	NoTestFlag = &noTest
+	ExecFn = func(_ *goyek.A, _ string, _ ...cmd.Option) bool {
+		return true
+	}
+	output := `[{"Name": "api", "Path": "example.com/m/api", "Funcs": [
+		{"Name": "NewT", "Position": {"File": "work/api/t.go", "Line": 3, "Col": 6}},
+		{"Name": "helper", "Position": {"File": "work/api/t.go", "Line": 9, "Col": 6}}
+	]}]`
+	tests := map[string]struct {
+		options      DeadcodeOptions
+		allowlist    string
+		deadcodeOk   bool
+		want         bool
+		wantFindings int
+	}{
+		"bad allowlist": {
+			options:   DeadcodeOptions{Fail: true},
+			allowlist: "{",
+			want:      false,
+		},
+		"deadcode fails": {
+			options:    DeadcodeOptions{Fail: true},
+			deadcodeOk: false,
+			want:       false,
+		},
+		"findings reported": {
+			options:      DeadcodeOptions{},
+			deadcodeOk:   true,
+			want:         true,
+			wantFindings: 2,
+		},
+		"findings fail": {
+			options:      DeadcodeOptions{Fail: true},
+			allowlist:    `[{"package": "example.com/m/api", "function": "New*"}]`,
+			deadcodeOk:   true,
+			want:         false,
+			wantFindings: 1,
+		},
+		"everything allowlisted": {
+			options:    DeadcodeOptions{Fail: true, AllowlistFile: "allowed.json"},
+			allowlist:  `[{"package": "example.com/m/...", "function": "*"}]`,
+			deadcodeOk: true,
+			want:       true,
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			delete(reportedFindings, "deadcode")
+			BuildFS = afero.NewMemMapFs()
+			_ = BuildFS.MkdirAll("work", dirMode)
+			if tt.allowlist != "" {
+				_ = afero.WriteFile(BuildFS, "work/"+tt.options.allowlistFile(), []byte(tt.allowlist), fileMode)
+			}
+			cmdCapture = func(_ *goyek.A, _ directedCommand) (bool, string, string) {
+				if !tt.deadcodeOk {
+					return false, "", "cannot load packages"
+				}
+				return true, output, ""
+			}
+			if got := DeadcodeWithOptions(nil, tt.options); got != tt.want {
+				t.Errorf("DeadcodeWithOptions() = %v, want %v", got, tt.want)
+			}
+			if got := len(FindingsOf("deadcode")); got != tt.wantFindings {
+				t.Errorf("DeadcodeWithOptions() findings = %d, want %d", got, tt.wantFindings)
+			}
+		})
+	}
+}
@@ -0,0 +1,218 @@
+package tools_build
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io/fs"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/spf13/afero"
+	"golang.org/x/mod/modfile"
+)
+
+// Copyright © 2026 Marc Johnson (marc.johnson27591@gmail.com)
+
+// moduleSnapshot records the contents of a module's go.mod and go.sum files
+type moduleSnapshot struct {
+	// dir is the module's directory, relative to WorkingDir()
+	dir         string
+	goMod       []byte
+	goSum       []byte
+	goSumExists bool
+}
+
+// takeModuleSnapshot reads the go.mod and go.sum files in the module
+// directory, which is relative to WorkingDir(); a missing go.sum file is not
+// an error. Returns false, after reporting the error, on failure
+func takeModuleSnapshot(dir string) (moduleSnapshot, bool) {
+	snapshot := moduleSnapshot{dir: dir}
+	goModFile := filepath.Join(WorkingDir(), dir, "go.mod")
+	content, err := afero.ReadFile(BuildFS, goModFile)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "error %v reading %q\n", err, goModFile)
+		return snapshot, false
+	}
+	snapshot.goMod = content
+	goSumFile := filepath.Join(WorkingDir(), dir, "go.sum")
+	if exists, _ := afero.Exists(BuildFS, goSumFile); exists {
+		if content, err = afero.ReadFile(BuildFS, goSumFile); err != nil {
+			fmt.Fprintf(os.Stderr, "error %v reading %q\n", err, goSumFile)
+			return snapshot, false
+		}
+		snapshot.goSum = content
+		snapshot.goSumExists = true
+	}
+	return snapshot, true
+}
+
+// changed returns true if the go.mod or go.sum file differs from the other
+// snapshot's
+func (ms moduleSnapshot) changed(other moduleSnapshot) bool {
+	return !bytes.Equal(ms.goMod, other.goMod) || !bytes.Equal(ms.goSum, other.goSum) ||
+		ms.goSumExists != other.goSumExists
+}
+
+// restore rewrites the module's go.mod and go.sum files as recorded in the
+// snapshot, removing a go.sum file that did not exist when the snapshot was
+// taken; returns false, after reporting the error, on failure
+func (ms moduleSnapshot) restore() bool {
+	if !writeWorkingFile(filepath.Join(ms.dir, "go.mod"), ms.goMod) {
+		return false
+	}
+	if ms.goSumExists {
+		return writeWorkingFile(filepath.Join(ms.dir, "go.sum"), ms.goSum)
+	}
+	goSumFile := filepath.Join(WorkingDir(), ms.dir, "go.sum")
+	if err := BuildFS.Remove(goSumFile); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		fmt.Fprintf(os.Stderr, "error %v removing %q\n", err, goSumFile)
+		return false
+	}
+	return true
+}
+
+// parse parses the snapshot's go.mod file
+func (ms moduleSnapshot) parse() (*modfile.File, bool) {
+	fileName := filepath.Join(WorkingDir(), ms.dir, "go.mod")
+	file, err := modfile.Parse(fileName, ms.goMod, nil)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "error %v parsing %q\n", err, fileName)
+		return nil, false
+	}
+	return file, true
+}
+
+// requirements parses the snapshot's go.mod file, returning the module path
+// and the version of each required module, keyed by module path
+func (ms moduleSnapshot) requirements() (string, map[string]string, bool) {
+	file, ok := ms.parse()
+	if !ok {
+		return "", nil, false
+	}
+	module := goModule{dir: ms.dir, file: file}.name()
+	versions := map[string]string{}
+	for _, requirement := range file.Require {
+		versions[requirement.Mod.Path] = requirement.Mod.Version
+	}
+	return module, versions, true
+}
+
+// dependencyChange describes a change to one of a module's requirements
+type dependencyChange struct {
+	module     string
+	dependency string
+	// oldVersion is empty if the dependency was added
+	oldVersion string
+	// newVersion is empty if the dependency was removed
+	newVersion string
+}
+
+func (dc dependencyChange) kind() string {
+	switch {
+	case dc.oldVersion == "":
+		return "added"
+	case dc.newVersion == "":
+		return "removed"
+	default:
+		return "updated"
+	}
+}
+
+// dependencyChanges compares a module's requirements before and after an
+// update, returning the changes sorted by dependency path
+func dependencyChanges(before, after moduleSnapshot) ([]dependencyChange, bool) {
+	_, oldVersions, ok := before.requirements()
+	if !ok {
+		return nil, false
+	}
+	module, newVersions, ok := after.requirements()
+	if !ok {
+		return nil, false
+	}
+	dependencies := slices.Collect(maps.Keys(oldVersions))
+	for dependency := range newVersions {
+		if _, found := oldVersions[dependency]; !found {
+			dependencies = append(dependencies, dependency)
+		}
+	}
+	slices.Sort(dependencies)
+	changes := make([]dependencyChange, 0)
+	for _, dependency := range dependencies {
+		if oldVersions[dependency] != newVersions[dependency] {
+			changes = append(changes, dependencyChange{
+				module:     module,
+				dependency: dependency,
+				oldVersion: oldVersions[dependency],
+				newVersion: newVersions[dependency],
+			})
+		}
+	}
+	return changes, true
+}
+
+// dependencyChangeTable renders the changes, and the reverted changes, as
+// tables for the console
+func dependencyChangeTable(changes, reverted []dependencyChange) string {
+	sections := make([]string, 0, 2)
+	if len(changes) == 0 {
+		sections = append(sections, "no dependency changes")
+	} else {
+		sections = append(sections, changeTable(changes))
+	}
+	if len(reverted) != 0 {
+		sections = append(sections, "reverted dependency changes (verification failed):\n"+changeTable(reverted))
+	}
+	return strings.Join(sections, "\n")
+}
+
+func changeTable(changes []dependencyChange) string {
+	builder := &strings.Builder{}
+	w := tabwriter.NewWriter(builder, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "module\tdependency\told\tnew\tchange")
+	for _, change := range changes {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", change.module, change.dependency,
+			orDash(change.oldVersion), orDash(change.newVersion), change.kind())
+	}
+	_ = w.Flush()
+	return strings.TrimSuffix(builder.String(), "\n")
+}
+
+// dependencyChangeMarkdown renders the changes, and the reverted changes, as
+// markdown, suitable for a pull request description
+func dependencyChangeMarkdown(changes, reverted []dependencyChange) string {
+	builder := &strings.Builder{}
+	builder.WriteString("## Dependency updates\n\n")
+	if len(changes) == 0 {
+		builder.WriteString("No dependency changes.\n")
+	} else {
+		builder.WriteString(changeMarkdownTable(changes))
+	}
+	if len(reverted) != 0 {
+		builder.WriteString("\n### Reverted updates\n\nThese updates were reverted because the build or the tests failed.\n\n")
+		builder.WriteString(changeMarkdownTable(reverted))
+	}
+	return builder.String()
+}
+
+func changeMarkdownTable(changes []dependencyChange) string {
+	builder := &strings.Builder{}
+	builder.WriteString("| Module | Dependency | Old | New | Change |\n")
+	builder.WriteString("| --- | --- | --- | --- | --- |\n")
+	for _, change := range changes {
+		fmt.Fprintf(builder, "| `%s` | `%s` | %s | %s | %s |\n", change.module, change.dependency,
+			orDash(change.oldVersion), orDash(change.newVersion), change.kind())
+	}
+	return builder.String()
+}
+
+func orDash(s string) string {
+	if s == "" {
+		return "-"
+	}
+	return s
+}
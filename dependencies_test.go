@@ -0,0 +1,276 @@
+package tools_build
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/spf13/afero"
+)
+
+// Copyright © 2026 Marc Johnson (marc.johnson27591@gmail.com)
+
+func Test_takeModuleSnapshot(t *testing.T) {
+	originalBuildFS := BuildFS
+	originalCachedWorkingDir := CachedWorkingDir
+	defer func() {
+		BuildFS = originalBuildFS
+		CachedWorkingDir = originalCachedWorkingDir
+	}()
+	BuildFS = afero.NewMemMapFs()
+	CachedWorkingDir = "work"
+	_ = BuildFS.MkdirAll("work/a", dirMode)
+	_ = BuildFS.MkdirAll("work/b", dirMode)
+	_ = BuildFS.MkdirAll("work/c", dirMode)
+	_ = afero.WriteFile(BuildFS, "work/a/go.mod", []byte("module a\n"), fileMode)
+	_ = afero.WriteFile(BuildFS, "work/a/go.sum", []byte("sums\n"), fileMode)
+	_ = afero.WriteFile(BuildFS, "work/b/go.mod", []byte("module b\n"), fileMode)
+	tests := map[string]struct {
+		dir    string
+		want   moduleSnapshot
+		wantOk bool
+	}{
+		"with go.sum": {
+			dir:    "a",
+			want:   moduleSnapshot{dir: "a", goMod: []byte("module a\n"), goSum: []byte("sums\n"), goSumExists: true},
+			wantOk: true,
+		},
+		"without go.sum": {
+			dir:    "b",
+			want:   moduleSnapshot{dir: "b", goMod: []byte("module b\n")},
+			wantOk: true,
+		},
+		"without go.mod": {
+			dir:    "c",
+			want:   moduleSnapshot{dir: "c"},
+			wantOk: false,
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, gotOk := takeModuleSnapshot(tt.dir)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("takeModuleSnapshot() got = %v, want %v", got, tt.want)
+			}
+			if gotOk != tt.wantOk {
+				t.Errorf("takeModuleSnapshot() gotOk = %v, want %v", gotOk, tt.wantOk)
+			}
+		})
+	}
+}
+
+func Test_moduleSnapshot_changed(t *testing.T) {
+	base := moduleSnapshot{goMod: []byte("module m\n"), goSum: []byte("sums\n"), goSumExists: true}
+	tests := map[string]struct {
+		other moduleSnapshot
+		want  bool
+	}{
+		"same":           {other: moduleSnapshot{goMod: []byte("module m\n"), goSum: []byte("sums\n"), goSumExists: true}, want: false},
+		"go.mod differs": {other: moduleSnapshot{goMod: []byte("module n\n"), goSum: []byte("sums\n"), goSumExists: true}, want: true},
+		"go.sum differs": {other: moduleSnapshot{goMod: []byte("module m\n"), goSum: []byte("more sums\n"), goSumExists: true}, want: true},
+		"go.sum missing": {other: moduleSnapshot{goMod: []byte("module m\n")}, want: true},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			if got := base.changed(tt.other); got != tt.want {
+				t.Errorf("changed() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_moduleSnapshot_restore(t *testing.T) {
+	originalBuildFS := BuildFS
+	originalCachedWorkingDir := CachedWorkingDir
+	defer func() {
+		BuildFS = originalBuildFS
+		CachedWorkingDir = originalCachedWorkingDir
+	}()
+	CachedWorkingDir = "work"
+	tests := map[string]struct {
+		snapshot  moduleSnapshot
+		wantGoMod string
+		wantGoSum string
+		want      bool
+	}{
+		"with go.sum": {
+			snapshot:  moduleSnapshot{dir: "a", goMod: []byte("module a\n"), goSum: []byte("sums\n"), goSumExists: true},
+			wantGoMod: "module a\n",
+			wantGoSum: "sums\n",
+			want:      true,
+		},
+		"without go.sum": {
+			snapshot:  moduleSnapshot{dir: "a", goMod: []byte("module a\n")},
+			wantGoMod: "module a\n",
+			want:      true,
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			BuildFS = afero.NewMemMapFs()
+			_ = BuildFS.MkdirAll("work/a", dirMode)
+			_ = afero.WriteFile(BuildFS, "work/a/go.mod", []byte("module a\n\nrequire b v1.1.0\n"), fileMode)
+			_ = afero.WriteFile(BuildFS, "work/a/go.sum", []byte("new sums\n"), fileMode)
+			if got := tt.snapshot.restore(); got != tt.want {
+				t.Errorf("restore() = %v, want %v", got, tt.want)
+			}
+			goMod, _ := afero.ReadFile(BuildFS, "work/a/go.mod")
+			if string(goMod) != tt.wantGoMod {
+				t.Errorf("restore() go.mod = %q, want %q", goMod, tt.wantGoMod)
+			}
+			goSum, err := afero.ReadFile(BuildFS, "work/a/go.sum")
+			if string(goSum) != tt.wantGoSum {
+				t.Errorf("restore() go.sum = %q, want %q", goSum, tt.wantGoSum)
+			}
+			if !tt.snapshot.goSumExists && err == nil {
+				t.Errorf("restore() did not remove go.sum")
+			}
+		})
+	}
+}
+
+func Test_dependencyChanges(t *testing.T) {
+	originalCachedWorkingDir := CachedWorkingDir
+	defer func() {
+		CachedWorkingDir = originalCachedWorkingDir
+	}()
+	// keeps WorkingDir() from getting exercised
+	CachedWorkingDir = "work"
+	tests := map[string]struct {
+		before moduleSnapshot
+		after  moduleSnapshot
+		want   []dependencyChange
+		wantOk bool
+	}{
+		"bad before": {
+			before: moduleSnapshot{goMod: []byte("modulo m\n")},
+			after:  moduleSnapshot{goMod: []byte("module m\n")},
+			wantOk: false,
+		},
+		"bad after": {
+			before: moduleSnapshot{goMod: []byte("module m\n")},
+			after:  moduleSnapshot{goMod: []byte("modulo m\n")},
+			wantOk: false,
+		},
+		"no changes": {
+			before: moduleSnapshot{goMod: []byte("module m\n\nrequire example.com/a v1.0.0\n")},
+			after:  moduleSnapshot{goMod: []byte("module m\n\nrequire example.com/a v1.0.0 // indirect\n")},
+			want:   []dependencyChange{},
+			wantOk: true,
+		},
+		"unnamed module": {
+			before: moduleSnapshot{dir: "sub", goMod: []byte("require example.com/a v1.0.0\n")},
+			after:  moduleSnapshot{dir: "sub", goMod: []byte("require example.com/a v1.2.0\n")},
+			want: []dependencyChange{
+				{module: "sub", dependency: "example.com/a", oldVersion: "v1.0.0", newVersion: "v1.2.0"},
+			},
+			wantOk: true,
+		},
+		"changes": {
+			before: moduleSnapshot{goMod: []byte("module m\n\nrequire (\n\texample.com/b v1.0.0\n\texample.com/c v1.0.0\n)\n")},
+			after:  moduleSnapshot{goMod: []byte("module m\n\nrequire (\n\texample.com/a v0.1.0\n\texample.com/c v1.0.1\n)\n")},
+			want: []dependencyChange{
+				{module: "m", dependency: "example.com/a", newVersion: "v0.1.0"},
+				{module: "m", dependency: "example.com/b", oldVersion: "v1.0.0"},
+				{module: "m", dependency: "example.com/c", oldVersion: "v1.0.0", newVersion: "v1.0.1"},
+			},
+			wantOk: true,
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, gotOk := dependencyChanges(tt.before, tt.after)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("dependencyChanges() got = %v, want %v", got, tt.want)
+			}
+			if gotOk != tt.wantOk {
+				t.Errorf("dependencyChanges() gotOk = %v, want %v", gotOk, tt.wantOk)
+			}
+		})
+	}
+}
+
+func Test_dependencyChangeTable(t *testing.T) {
+	tests := map[string]struct {
+		changes  []dependencyChange
+		reverted []dependencyChange
+		want     string
+	}{
+		"none": {
+			changes: nil,
+			want:    "no dependency changes",
+		},
+		"some": {
+			changes: []dependencyChange{
+				{module: "m", dependency: "example.com/a", newVersion: "v0.1.0"},
+				{module: "m", dependency: "example.com/long", oldVersion: "v1.0.0", newVersion: "v1.0.1"},
+			},
+			want: "" +
+				"module  dependency        old     new     change\n" +
+				"m       example.com/a     -       v0.1.0  added\n" +
+				"m       example.com/long  v1.0.0  v1.0.1  updated",
+		},
+		"reverted": {
+			reverted: []dependencyChange{
+				{module: "m", dependency: "example.com/a", oldVersion: "v1.0.0", newVersion: "v2.0.0+incompatible"},
+			},
+			want: "" +
+				"no dependency changes\n" +
+				"reverted dependency changes (verification failed):\n" +
+				"module  dependency     old     new                  change\n" +
+				"m       example.com/a  v1.0.0  v2.0.0+incompatible  updated",
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			if got := dependencyChangeTable(tt.changes, tt.reverted); got != tt.want {
+				t.Errorf("dependencyChangeTable() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_dependencyChangeMarkdown(t *testing.T) {
+	tests := map[string]struct {
+		changes  []dependencyChange
+		reverted []dependencyChange
+		want     string
+	}{
+		"none": {
+			changes: nil,
+			want:    "## Dependency updates\n\nNo dependency changes.\n",
+		},
+		"some": {
+			changes: []dependencyChange{
+				{module: "m", dependency: "example.com/b", oldVersion: "v1.0.0"},
+			},
+			want: "## Dependency updates\n\n" +
+				"| Module | Dependency | Old | New | Change |\n" +
+				"| --- | --- | --- | --- | --- |\n" +
+				"| `m` | `example.com/b` | v1.0.0 | - | removed |\n",
+		},
+		"reverted": {
+			changes: []dependencyChange{
+				{module: "m", dependency: "example.com/b", oldVersion: "v1.0.0"},
+			},
+			reverted: []dependencyChange{
+				{module: "n", dependency: "example.com/c", oldVersion: "v1.0.0", newVersion: "v1.1.0"},
+			},
+			want: "## Dependency updates\n\n" +
+				"| Module | Dependency | Old | New | Change |\n" +
+				"| --- | --- | --- | --- | --- |\n" +
+				"| `m` | `example.com/b` | v1.0.0 | - | removed |\n" +
+				"\n### Reverted updates\n\n" +
+				"These updates were reverted because the build or the tests failed.\n\n" +
+				"| Module | Dependency | Old | New | Change |\n" +
+				"| --- | --- | --- | --- | --- |\n" +
+				"| `n` | `example.com/c` | v1.0.0 | v1.1.0 | updated |\n",
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			if got := dependencyChangeMarkdown(tt.changes, tt.reverted); got != tt.want {
+				t.Errorf("dependencyChangeMarkdown() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,151 @@
+package tools_build
+
+import (
+	"fmt"
+	"maps"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/goyek/goyek/v3"
+)
+
+// Copyright © 2026 Marc Johnson (marc.johnson27591@gmail.com)
+
+// DiffCoverage determines which lines of Go source have changed relative to
+// baseRef (a branch, tag or commit known to git, such as "main"), using the
+// merge base of baseRef and the working tree, and measures how many of the
+// changed lines that contain statements are covered according to the coverage
+// profile. Uncovered changed lines are reported by file. Returns false on
+// failure, or if the percentage of changed lines that are covered is below
+// minimum
+func DiffCoverage(a *goyek.A, coverageDataFile, baseRef string, minimum float64) bool {
+	if baseRef == "" || strings.HasPrefix(baseRef, "-") {
+		fmt.Fprintf(os.Stderr, "cannot accept %q as a valid git reference\n", baseRef)
+		return false
+	}
+	profile, ok := readCoverageProfile(coverageDataFile)
+	if !ok {
+		return false
+	}
+	modules, ok := findModules()
+	if !ok {
+		return false
+	}
+	fmt.Printf("determining changes relative to %q\n", baseRef)
+	state, stdout, stderr := cmdCapture(a, directedCommand{
+		command: fmt.Sprintf("git diff --unified=0 --no-color --no-ext-diff --merge-base %s -- '*.go'", quoteArg(baseRef)),
+		dir:     WorkingDir(),
+	})
+	if stderr != "" {
+		printIt(stderr)
+	}
+	if !state {
+		return false
+	}
+	hits := map[string]lineHits{}
+	for file, fileHits := range profile.lineHits() {
+		hits[repoRelativePath(file, modules)] = fileHits
+	}
+	var coverable, covered int
+	report := make([]string, 0)
+	changes := changedLines(stdout)
+	for _, file := range slices.Sorted(maps.Keys(changes)) {
+		if !MatchGoSource(file[strings.LastIndex(file, "/")+1:]) {
+			continue
+		}
+		uncovered := make([]int, 0)
+		for _, line := range changes[file] {
+			count, found := hits[file][line]
+			if !found {
+				continue
+			}
+			coverable++
+			if count > 0 {
+				covered++
+			} else {
+				uncovered = append(uncovered, line)
+			}
+		}
+		if len(uncovered) != 0 {
+			report = append(report, fmt.Sprintf("\t%s: %s", file, lineRanges(uncovered)))
+		}
+	}
+	percent := coverageStats{statements: coverable, covered: covered}.percent()
+	if len(report) != 0 {
+		printIt("uncovered changed lines:\n" + strings.Join(report, "\n"))
+	}
+	printIt(fmt.Sprintf("diff coverage: %.1f%% (%d of %d changed lines covered), minimum %.1f%%", percent, covered, coverable, minimum))
+	if percent < minimum {
+		fmt.Fprintln(os.Stderr, "diff coverage is below the required minimum")
+		return false
+	}
+	return true
+}
+
+// changedLines parses the output of 'git diff --unified=0', returning the
+// added or modified line numbers (in the new version) of each file, keyed by
+// the file's path; deleted files are ignored
+func changedLines(diff string) map[string][]int {
+	changes := map[string][]int{}
+	file := ""
+	for line := range strings.SplitSeq(diff, "\n") {
+		switch {
+		case strings.HasPrefix(line, "+++ "):
+			file = ""
+			if name, found := strings.CutPrefix(strings.TrimSpace(line[4:]), "b/"); found {
+				file = name
+			}
+		case strings.HasPrefix(line, "@@ ") && file != "":
+			start, count, ok := parseHunkHeader(line)
+			if !ok {
+				continue
+			}
+			for lineNumber := start; lineNumber < start+count; lineNumber++ {
+				changes[file] = append(changes[file], lineNumber)
+			}
+		}
+	}
+	return changes
+}
+
+// parseHunkHeader extracts the new-file line range from a hunk header of the
+// form '@@ -oldStart[,oldCount] +newStart[,newCount] @@'
+func parseHunkHeader(header string) (start, count int, ok bool) {
+	fields := strings.Fields(header)
+	if len(fields) < 3 || !strings.HasPrefix(fields[2], "+") {
+		return 0, 0, false
+	}
+	startText, countText, hasCount := strings.Cut(fields[2][1:], ",")
+	var err error
+	if start, err = strconv.Atoi(startText); err != nil {
+		return 0, 0, false
+	}
+	count = 1
+	if hasCount {
+		if count, err = strconv.Atoi(countText); err != nil {
+			return 0, 0, false
+		}
+	}
+	return start, count, true
+}
+
+// lineRanges renders a sorted list of line numbers compactly, such as
+// "3-5, 9"
+func lineRanges(lines []int) string {
+	ranges := make([]string, 0)
+	for index := 0; index < len(lines); {
+		end := index
+		for end+1 < len(lines) && lines[end+1] == lines[end]+1 {
+			end++
+		}
+		if end == index {
+			ranges = append(ranges, strconv.Itoa(lines[index]))
+		} else {
+			ranges = append(ranges, fmt.Sprintf("%d-%d", lines[index], lines[end]))
+		}
+		index = end + 1
+	}
+	return strings.Join(ranges, ", ")
+}
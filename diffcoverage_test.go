@@ -0,0 +1,155 @@
+package tools_build
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/goyek/goyek/v3"
+	"github.com/spf13/afero"
+)
+
+// Copyright © 2026 Marc Johnson (marc.johnson27591@gmail.com)
+
+const sampleDiff = "" +
+	"diff --git a/a/a.go b/a/a.go\n" +
+	"index 1111111..2222222 100644\n" +
+	"--- a/a/a.go\n" +
+	"+++ b/a/a.go\n" +
+	"@@ -3,0 +4,3 @@ func A() {\n" +
+	"+\tx := 1\n" +
+	"+\ty := 2\n" +
+	"+\treturn x + y\n" +
+	"@@ -10 +13 @@ func B() {\n" +
+	"-\treturn 0\n" +
+	"+\treturn 1\n" +
+	"@@ -20,2 +23,0 @@ func C() {\n" +
+	"diff --git a/a/a_test.go b/a/a_test.go\n" +
+	"--- a/a/a_test.go\n" +
+	"+++ b/a/a_test.go\n" +
+	"@@ -1 +1 @@\n" +
+	"+package a\n" +
+	"diff --git a/gone.go b/gone.go\n" +
+	"--- a/gone.go\n" +
+	"+++ /dev/null\n" +
+	"@@ -1,3 +0,0 @@\n"
+
+func TestDiffCoverage(t *testing.T) {
+	originalBuildFS := BuildFS
+	originalCachedWorkingDir := CachedWorkingDir
+	originalCmdCapture := cmdCapture
+	defer func() {
+		BuildFS = originalBuildFS
+		CachedWorkingDir = originalCachedWorkingDir
+		cmdCapture = originalCmdCapture
+	}()
+	CachedWorkingDir = "work"
+	BuildFS = afero.NewMemMapFs()
+	_ = BuildFS.MkdirAll("work", dirMode)
+	_ = afero.WriteFile(BuildFS, "work/go.mod", []byte("module example.com/m\n"), fileMode)
+	_ = afero.WriteFile(BuildFS, "work/coverage.out", []byte(""+
+		"mode: set\n"+
+		"example.com/m/a/a.go:4.2,5.10 2 1\n"+
+		"example.com/m/a/a.go:6.2,6.14 1 0\n"+
+		"example.com/m/a/a.go:13.2,13.10 1 0\n"), fileMode)
+	tests := map[string]struct {
+		baseRef     string
+		minimum     float64
+		gitSucceeds bool
+		wantCommand string
+		want        bool
+	}{
+		"bad ref": {
+			baseRef: "--output=/etc/passwd",
+			want:    false,
+		},
+		"git fails": {
+			baseRef:     "main",
+			gitSucceeds: false,
+			wantCommand: "git diff --unified=0 --no-color --no-ext-diff --merge-base main -- '*.go'",
+			want:        false,
+		},
+		"below minimum": {
+			baseRef:     "main",
+			minimum:     60,
+			gitSucceeds: true,
+			wantCommand: "git diff --unified=0 --no-color --no-ext-diff --merge-base main -- '*.go'",
+			want:        false,
+		},
+		"meets minimum": {
+			baseRef:     "main",
+			minimum:     50,
+			gitSucceeds: true,
+			wantCommand: "git diff --unified=0 --no-color --no-ext-diff --merge-base main -- '*.go'",
+			want:        true,
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			var gotCommand string
+			cmdCapture = func(_ *goyek.A, dC directedCommand) (bool, string, string) {
+				gotCommand = dC.command
+				return tt.gitSucceeds, sampleDiff, ""
+			}
+			if got := DiffCoverage(nil, "coverage.out", tt.baseRef, tt.minimum); got != tt.want {
+				t.Errorf("DiffCoverage() = %v, want %v", got, tt.want)
+			}
+			if gotCommand != tt.wantCommand {
+				t.Errorf("DiffCoverage() command = %q, want %q", gotCommand, tt.wantCommand)
+			}
+		})
+	}
+}
+
+func Test_changedLines(t *testing.T) {
+	want := map[string][]int{
+		"a/a.go":      {4, 5, 6, 13},
+		"a/a_test.go": {1},
+	}
+	if got := changedLines(sampleDiff); !reflect.DeepEqual(got, want) {
+		t.Errorf("changedLines() = %v, want %v", got, want)
+	}
+}
+
+func Test_parseHunkHeader(t *testing.T) {
+	tests := map[string]struct {
+		header    string
+		wantStart int
+		wantCount int
+		wantOk    bool
+	}{
+		"range":        {header: "@@ -3,0 +4,3 @@ func A() {", wantStart: 4, wantCount: 3, wantOk: true},
+		"single line":  {header: "@@ -10 +13 @@", wantStart: 13, wantCount: 1, wantOk: true},
+		"deletion":     {header: "@@ -20,2 +19,0 @@", wantStart: 19, wantCount: 0, wantOk: true},
+		"truncated":    {header: "@@ -20,2", wantOk: false},
+		"bad start":    {header: "@@ -1 +x,2 @@", wantOk: false},
+		"bad count":    {header: "@@ -1 +2,y @@", wantOk: false},
+		"missing plus": {header: "@@ -1 -2 @@", wantOk: false},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			gotStart, gotCount, gotOk := parseHunkHeader(tt.header)
+			if gotStart != tt.wantStart || gotCount != tt.wantCount || gotOk != tt.wantOk {
+				t.Errorf("parseHunkHeader() = %d, %d, %v, want %d, %d, %v",
+					gotStart, gotCount, gotOk, tt.wantStart, tt.wantCount, tt.wantOk)
+			}
+		})
+	}
+}
+
+func Test_lineRanges(t *testing.T) {
+	tests := map[string]struct {
+		lines []int
+		want  string
+	}{
+		"none":   {lines: nil, want: ""},
+		"single": {lines: []int{7}, want: "7"},
+		"mixed":  {lines: []int{3, 4, 5, 9, 11, 12}, want: "3-5, 9, 11-12"},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			if got := lineRanges(tt.lines); got != tt.want {
+				t.Errorf("lineRanges() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
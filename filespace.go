@@ -206,6 +206,10 @@ func matchAnyGoFile(name string) bool {
 	return endsIn(name, ".go")
 }
 
+func matchGoTest(name string) bool {
+	return endsIn(name, "_test.go")
+}
+
 func matchModuleFile(name string) bool {
 	return name == "go.mod"
 }
@@ -213,3 +217,14 @@ func matchModuleFile(name string) bool {
 func startsWith(s, prefix string) bool {
 	return strings.HasPrefix(s, prefix)
 }
+
+// writeWorkingFile writes the content to the named file, which is located relative to WorkingDir(); returns false,
+// after reporting the error, if the file cannot be written
+func writeWorkingFile(name string, content []byte) bool {
+	path := filepath.Join(WorkingDir(), name)
+	if err := afero.WriteFile(BuildFS, path, content, 0o644); err != nil {
+		fmt.Fprintf(os.Stderr, "error %v writing %q\n", err, path)
+		return false
+	}
+	return true
+}
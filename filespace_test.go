@@ -657,3 +657,37 @@ func Test_startsWith(t *testing.T) {
 		})
 	}
 }
+
+func Test_writeWorkingFile(t *testing.T) {
+	originalBuildFS := BuildFS
+	originalCachedWorkingDir := CachedWorkingDir
+	defer func() {
+		BuildFS = originalBuildFS
+		CachedWorkingDir = originalCachedWorkingDir
+	}()
+	CachedWorkingDir = "work"
+	tests := map[string]struct {
+		fs   afero.Fs
+		want bool
+	}{
+		"read-only": {
+			fs:   afero.NewReadOnlyFs(afero.NewMemMapFs()),
+			want: false,
+		},
+		"writable": {
+			fs:   afero.NewMemMapFs(),
+			want: true,
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			BuildFS = tt.fs
+			if got := writeWorkingFile("out.txt", []byte("data")); got != tt.want {
+				t.Errorf("writeWorkingFile() = %v, want %v", got, tt.want)
+			}
+			if got, _ := afero.Exists(BuildFS, filepath.Join("work", "out.txt")); got != tt.want {
+				t.Errorf("writeWorkingFile() file exists = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,577 @@
+package tools_build
+
+import (
+	"cmp"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"maps"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/goyek/goyek/v3"
+)
+
+// Copyright © 2026 Marc Johnson (marc.johnson27591@gmail.com)
+
+// Findings formats, as accepted by Findings.Render
+const (
+	FindingsText     = "text"
+	FindingsJSON     = "json"
+	FindingsMarkdown = "markdown"
+)
+
+// FindingsFormatFlag is a flag that selects the format in which Lint, NilAway, Deadcode and GoFix print their findings
+var FindingsFormatFlag = flag.String(
+	"findingsformat",
+	FindingsText,
+	"set to the format (text, json or markdown) in which static analysis findings are printed")
+
+// reportedFindings holds the findings most recently reported by each tool
+var reportedFindings = map[string]Findings{}
+
+// Finding severities, matching the SARIF result levels
+const (
+	SeverityError   = "error"
+	SeverityWarning = "warning"
+	SeverityNote    = "note"
+)
+
+// Finding is a problem reported by a static analysis tool
+type Finding struct {
+	// Tool is the name of the tool that reported the finding, such as
+	// "gocritic"
+	Tool string `json:"tool"`
+	// File is the path of the affected file, relative to WorkingDir() when
+	// possible; it is empty if the finding has no location in the code
+	File   string `json:"file,omitempty"`
+	Line   int    `json:"line,omitempty"`
+	Column int    `json:"column,omitempty"`
+	// Rule identifies the check that produced the finding, such as a gocritic
+	// checker name or an OSV ID
+	Rule    string `json:"rule"`
+	Message string `json:"message"`
+	// Severity is one of SeverityError, SeverityWarning, or SeverityNote
+	Severity string `json:"severity"`
+}
+
+// String renders the finding in the conventional file:line:column format
+func (f Finding) String() string {
+	location := f.File
+	if location == "" {
+		location = f.Tool
+	} else if f.Line > 0 {
+		location = fmt.Sprintf("%s:%d", location, f.Line)
+		if f.Column > 0 {
+			location = fmt.Sprintf("%s:%d", location, f.Column)
+		}
+	}
+	return fmt.Sprintf("%s: %s: %s", location, f.Rule, f.Message)
+}
+
+// Findings is a collection of findings, as returned by LintFindings,
+// NilAwayFindings, DeadcodeFindings and GoFixFindings; the number of findings
+// is simply its length
+type Findings []Finding
+
+// CountByTool returns the number of findings reported by each tool
+func (fs Findings) CountByTool() map[string]int {
+	return fs.countBy(func(f Finding) string { return f.Tool })
+}
+
+// CountBySeverity returns the number of findings of each severity
+func (fs Findings) CountBySeverity() map[string]int {
+	return fs.countBy(func(f Finding) string { return f.Severity })
+}
+
+func (fs Findings) countBy(key func(Finding) string) map[string]int {
+	counts := map[string]int{}
+	for _, f := range fs {
+		counts[key(f)]++
+	}
+	return counts
+}
+
+// InPaths returns the findings whose files match any of the path patterns; a
+// pattern is either a file path, or a directory path followed by "/...", such
+// as "internal/...", and paths are relative to WorkingDir()
+func (fs Findings) InPaths(patterns ...string) Findings {
+	return fs.filter(func(f Finding) bool { return f.File != "" && matchesAnyPackagePattern(f.File, patterns) })
+}
+
+// ExcludingPaths returns the findings whose files match none of the path
+// patterns (see InPaths); findings without a file are kept
+func (fs Findings) ExcludingPaths(patterns ...string) Findings {
+	return fs.filter(func(f Finding) bool { return f.File == "" || !matchesAnyPackagePattern(f.File, patterns) })
+}
+
+func (fs Findings) filter(keep func(Finding) bool) Findings {
+	kept := make(Findings, 0, len(fs))
+	for _, f := range fs {
+		if keep(f) {
+			kept = append(kept, f)
+		}
+	}
+	return kept
+}
+
+// Deduplicate returns the findings without repetitions, keeping the first of
+// each set of identical findings
+func (fs Findings) Deduplicate() Findings {
+	seen := map[Finding]bool{}
+	return fs.filter(func(f Finding) bool {
+		if seen[f] {
+			return false
+		}
+		seen[f] = true
+		return true
+	})
+}
+
+// Sorted returns a copy of the findings sorted by file, line, column, tool,
+// rule and message
+func (fs Findings) Sorted() Findings {
+	return slices.SortedStableFunc(slices.Values(fs), func(f1, f2 Finding) int {
+		return cmp.Or(
+			cmp.Compare(f1.File, f2.File),
+			cmp.Compare(f1.Line, f2.Line),
+			cmp.Compare(f1.Column, f2.Column),
+			cmp.Compare(f1.Tool, f2.Tool),
+			cmp.Compare(f1.Rule, f2.Rule),
+			cmp.Compare(f1.Message, f2.Message),
+		)
+	})
+}
+
+// Render renders the findings in the specified format: FindingsText (one
+// finding per line, see Finding.String), FindingsJSON, or FindingsMarkdown (a
+// table); returns false, after reporting the error, if the format is not
+// recognized
+func (fs Findings) Render(format string) (string, bool) {
+	switch format {
+	case FindingsText:
+		lines := make([]string, 0, len(fs))
+		for _, f := range fs {
+			lines = append(lines, f.String())
+		}
+		return strings.Join(lines, "\n"), true
+	case FindingsJSON:
+		findings := fs
+		if findings == nil {
+			findings = Findings{}
+		}
+		content, _ := json.MarshalIndent(findings, "", "  ")
+		return string(content), true
+	case FindingsMarkdown:
+		return fs.markdown(), true
+	default:
+		fmt.Fprintf(os.Stderr, "cannot accept %q as a findings format; use %q, %q or %q\n", format, FindingsText,
+			FindingsJSON, FindingsMarkdown)
+		return "", false
+	}
+}
+
+func (fs Findings) markdown() string {
+	if len(fs) == 0 {
+		return "no findings"
+	}
+	builder := &strings.Builder{}
+	builder.WriteString("| File | Line | Column | Tool | Rule | Severity | Message |\n")
+	builder.WriteString("| --- | ---: | ---: | --- | --- | --- | --- |\n")
+	for _, f := range fs {
+		fmt.Fprintf(builder, "| %s | %s | %s | %s | %s | %s | %s |\n", orDash(markdownCell(f.File)),
+			orDash(positionCell(f.Line)), orDash(positionCell(f.Column)), markdownCell(f.Tool), markdownCell(f.Rule),
+			f.Severity, markdownCell(f.Message))
+	}
+	return strings.TrimSuffix(builder.String(), "\n")
+}
+
+// markdownCell escapes the text so that it fits in a single table cell
+func markdownCell(s string) string {
+	return strings.ReplaceAll(strings.ReplaceAll(s, "|", "\\|"), "\n", " ")
+}
+
+func positionCell(n int) string {
+	if n <= 0 {
+		return ""
+	}
+	return strconv.Itoa(n)
+}
+
+// relativeFindingPath makes a path reported by a tool relative to
+// WorkingDir(), if possible, using forward slashes
+func relativeFindingPath(path string) string {
+	if filepath.IsAbs(path) {
+		if top, err := filepath.Abs(WorkingDir()); err == nil {
+			if relative, relErr := filepath.Rel(top, path); relErr == nil && !strings.HasPrefix(relative, "..") {
+				path = relative
+			}
+		}
+	}
+	return strings.TrimPrefix(canonicalPath(filepath.ToSlash(path)), "./")
+}
+
+// splitPosition splits a position of the form file:line:column, or
+// file:line, into its parts
+func splitPosition(position string) (file string, line, column int) {
+	file = position
+	numbers := make([]int, 0, 2)
+	for range 2 {
+		index := strings.LastIndex(file, ":")
+		if index < 0 {
+			break
+		}
+		number, err := strconv.Atoi(file[index+1:])
+		if err != nil {
+			break
+		}
+		numbers = append([]int{number}, numbers...)
+		file = file[:index]
+	}
+	switch len(numbers) {
+	case 2:
+		return file, numbers[0], numbers[1]
+	case 1:
+		return file, numbers[0], 0
+	default:
+		return position, 0, 0
+	}
+}
+
+var gocriticLine = regexp.MustCompile(`^(.+\.go:\d+(?::\d+)?): ([\w-]+): (.*)$`)
+
+// parseGocriticOutput extracts the findings from the output of 'gocritic
+// check', whose lines have the form 'file:line:column: checker: message'
+func parseGocriticOutput(output string) []Finding {
+	findings := make([]Finding, 0)
+	for line := range strings.SplitSeq(output, "\n") {
+		match := gocriticLine.FindStringSubmatch(strings.TrimSpace(line))
+		if match == nil {
+			continue
+		}
+		file, lineNumber, column := splitPosition(match[1])
+		findings = append(findings, Finding{
+			Tool:     "gocritic",
+			File:     relativeFindingPath(file),
+			Line:     lineNumber,
+			Column:   column,
+			Rule:     match[2],
+			Message:  match[3],
+			Severity: SeverityWarning,
+		})
+	}
+	return findings
+}
+
+// analysisDiagnostic is a diagnostic in the JSON written by analysis tools
+// built on golang.org/x/tools/go/analysis, when run with -json
+type analysisDiagnostic struct {
+	Category string `json:"category"`
+	Posn     string `json:"posn"`
+	Message  string `json:"message"`
+}
+
+// parseAnalysisJSON decodes the stream of JSON objects written by an analysis
+// tool run with -json; each object maps package paths to analyzer names to
+// either a list of diagnostics or an error. The analyzer name becomes the
+// finding's rule. Returns false, after reporting them, if any analyzer
+// failed
+func parseAnalysisJSON(tool, severity, output string) ([]Finding, bool) {
+	return parseAnalysisJSONExcluding(tool, severity, output, nil)
+}
+
+// parseAnalysisJSONExcluding works like parseAnalysisJSON, ignoring the
+// diagnostics and failures of packages matching any of the package patterns
+// (see matchesAnyPackagePattern); test variants of a package, such as "p
+// [p.test]", match the package's patterns
+func parseAnalysisJSONExcluding(tool, severity, output string, excluded []string) ([]Finding, bool) {
+	decoder := json.NewDecoder(strings.NewReader(output))
+	findings := make([]Finding, 0)
+	ok := true
+	for {
+		var packages map[string]map[string]json.RawMessage
+		err := decoder.Decode(&packages)
+		if errors.Is(err, io.EOF) {
+			return findings, ok
+		}
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "error %v parsing %s output\n", err, tool)
+			return nil, false
+		}
+		for _, pkg := range slices.Sorted(maps.Keys(packages)) {
+			if path, _, _ := strings.Cut(pkg, " "); matchesAnyPackagePattern(path, excluded) {
+				continue
+			}
+			for _, analyzer := range slices.Sorted(maps.Keys(packages[pkg])) {
+				var diagnostics []analysisDiagnostic
+				if json.Unmarshal(packages[pkg][analyzer], &diagnostics) != nil {
+					var failure struct {
+						Error string `json:"error"`
+					}
+					_ = json.Unmarshal(packages[pkg][analyzer], &failure)
+					fmt.Fprintf(os.Stderr, "%s: %s failed: %s\n", pkg, analyzer, failure.Error)
+					ok = false
+					continue
+				}
+				for _, diagnostic := range diagnostics {
+					file, line, column := splitPosition(diagnostic.Posn)
+					findings = append(findings, Finding{
+						Tool:     tool,
+						File:     relativeFindingPath(file),
+						Line:     line,
+						Column:   column,
+						Rule:     analyzer,
+						Message:  diagnostic.Message,
+						Severity: severity,
+					})
+				}
+			}
+		}
+	}
+}
+
+// deadcodePackage is a package in the JSON written by 'deadcode -json'
+type deadcodePackage struct {
+	Name  string
+	Path  string
+	Funcs []deadcodeFunction
+}
+
+type deadcodeFunction struct {
+	Name     string
+	Position struct {
+		File string
+		Line int
+		Col  int
+	}
+	Generated bool
+}
+
+// parseDeadcodeJSON decodes the output of 'deadcode -json', returning a
+// finding for each unreachable function
+func parseDeadcodeJSON(output string) ([]Finding, bool) {
+	packages, ok := parseDeadcodePackages(output)
+	if !ok {
+		return nil, false
+	}
+	return deadcodeFindings(packages), true
+}
+
+// parseDeadcodePackages decodes the output of 'deadcode -json'
+func parseDeadcodePackages(output string) ([]deadcodePackage, bool) {
+	var packages []deadcodePackage
+	if strings.TrimSpace(output) != "" {
+		if err := json.Unmarshal([]byte(output), &packages); err != nil {
+			fmt.Fprintf(os.Stderr, "error %v parsing deadcode output\n", err)
+			return nil, false
+		}
+	}
+	return packages, true
+}
+
+// deadcodeFindings returns a finding for each unreachable function
+func deadcodeFindings(packages []deadcodePackage) []Finding {
+	findings := make([]Finding, 0)
+	for _, pkg := range packages {
+		for _, function := range pkg.Funcs {
+			findings = append(findings, Finding{
+				Tool:     "deadcode",
+				File:     relativeFindingPath(function.Position.File),
+				Line:     function.Position.Line,
+				Column:   function.Position.Col,
+				Rule:     "unreachable-func",
+				Message:  fmt.Sprintf("%s.%s is unreachable", pkg.Path, function.Name),
+				Severity: SeverityWarning,
+			})
+		}
+	}
+	return findings
+}
+
+// vulnerabilityFindings converts vulnerabilities into findings; called
+// vulnerabilities are errors, located at the call in the code, and the others
+// are warnings without a location
+func vulnerabilityFindings(vulnerabilities []Vulnerability) []Finding {
+	findings := make([]Finding, 0, len(vulnerabilities))
+	for _, vulnerability := range vulnerabilities {
+		finding := Finding{
+			Tool:     "govulncheck",
+			Rule:     vulnerability.ID,
+			Severity: SeverityWarning,
+		}
+		fixed := "no fix is available"
+		if vulnerability.FixedVersion != "" {
+			fixed = "fixed in " + vulnerability.FixedVersion
+		}
+		finding.Message = fmt.Sprintf("%s@%s is vulnerable (%s)", vulnerability.Module, vulnerability.Version, fixed)
+		if vulnerability.Summary != "" {
+			finding.Message = vulnerability.Summary + ": " + finding.Message
+		}
+		if vulnerability.Called {
+			finding.Severity = SeverityError
+			finding.Message += "; " + vulnerability.Symbol + " is called"
+			finding.File = relativeFindingPath(vulnerability.File)
+			finding.Line = vulnerability.Line
+			finding.Column = vulnerability.Column
+		}
+		findings = append(findings, finding)
+	}
+	return findings
+}
+
+// GoFixFindings runs 'go fix -diff', without changing any files, and returns a
+// finding for each change that go fix would make; returns false if go fix
+// cannot be run
+func GoFixFindings(a *goyek.A) (Findings, bool) {
+	printIt("running go fix")
+	state, diffs := cmdOutput(a, "go fix -diff ./...")
+	if !state {
+		return nil, false
+	}
+	return parseGoFixDiff(diffs), true
+}
+
+var diffHunk = regexp.MustCompile(`^@@ -\d+(?:,\d+)? \+(\d+)(?:,(\d+))? @@`)
+
+// parseGoFixDiff extracts the findings from the unified diff written by 'go
+// fix -diff': one per hunk, located at the hunk's first line, or one per file
+// if the diff has no hunk headers
+func parseGoFixDiff(diff string) []Finding {
+	findings := make([]Finding, 0)
+	file := ""
+	hunks := 0
+	flush := func() {
+		if file != "" && hunks == 0 {
+			findings = append(findings, goFixFinding(file, 0, 0))
+		}
+	}
+	gitStyle := false
+	for line := range strings.SplitSeq(diff, "\n") {
+		if strings.HasPrefix(line, "--- ") {
+			gitStyle = strings.HasPrefix(line, "--- a/")
+			continue
+		}
+		if header, found := strings.CutPrefix(line, "+++ "); found {
+			flush()
+			header, _, _ = strings.Cut(header, "\t")
+			header = strings.TrimSuffix(header, " (new)")
+			if gitStyle {
+				header = strings.TrimPrefix(header, "b/")
+			}
+			file = relativeFindingPath(header)
+			hunks = 0
+			continue
+		}
+		if match := diffHunk.FindStringSubmatch(line); match != nil && file != "" {
+			start, _ := strconv.Atoi(match[1])
+			count := 1
+			if match[2] != "" {
+				count, _ = strconv.Atoi(match[2])
+			}
+			findings = append(findings, goFixFinding(file, start, count))
+			hunks++
+		}
+	}
+	flush()
+	return findings
+}
+
+func goFixFinding(file string, line, count int) Finding {
+	message := "go fix would change this file"
+	if line > 0 {
+		message = fmt.Sprintf("go fix would change lines %d-%d", line, line+max(count, 1)-1)
+	}
+	return Finding{Tool: "gofix", File: file, Line: line, Rule: "gofix", Message: message, Severity: SeverityNote}
+}
+
+// LintFindings runs gocritic, after making sure that it is up-to-date, as
+// directed by the command line flags (see FlagLintOptions), and returns its
+// findings; returns false if gocritic cannot be run
+func LintFindings(a *goyek.A) (Findings, bool) {
+	return LintFindingsWithOptions(a, FlagLintOptions())
+}
+
+// LintFindingsWithOptions runs gocritic, after making sure that it is
+// up-to-date, as directed by the options, and returns its findings, except
+// those in excluded paths; returns false if the options are invalid or
+// gocritic cannot be run
+func LintFindingsWithOptions(a *goyek.A, options LintOptions) (Findings, bool) {
+	return RunLinter(a, GocriticLinter{Options: options})
+}
+
+// NilAwayFindings runs nilaway, after making sure that it is up-to-date, and
+// returns its findings; returns false if nilaway cannot be run
+func NilAwayFindings(a *goyek.A) (Findings, bool) {
+	if !Install(a, "go.uber.org/nilaway/cmd/nilaway") {
+		return nil, false
+	}
+	printIt("running nilaway analysis")
+	state, stdout, stderr := cmdCapture(a, directedCommand{command: "nilaway -json ./...", dir: WorkingDir()})
+	if !state {
+		printIt(strings.TrimSpace(stdout + "\n" + stderr))
+		return nil, false
+	}
+	return parseAnalysisJSON("nilaway", SeverityError, stdout)
+}
+
+// DeadcodeFindings runs dead code analysis, after making sure that the
+// deadcode tool is up-to-date, and returns a finding for each unreachable
+// function; the -notest flag is honored. Returns false if deadcode cannot be
+// run
+func DeadcodeFindings(a *goyek.A) (Findings, bool) {
+	packages, ok := runDeadcode(a)
+	if !ok {
+		return nil, false
+	}
+	return deadcodeFindings(packages), true
+}
+
+// runDeadcode runs 'deadcode -json', after making sure that the deadcode tool
+// is up-to-date, and returns the packages containing unreachable functions;
+// the -notest flag is honored
+func runDeadcode(a *goyek.A) ([]deadcodePackage, bool) {
+	if !Install(a, "golang.org/x/tools/cmd/deadcode") {
+		return nil, false
+	}
+	command := "deadcode -json -test ."
+	if *NoTestFlag {
+		command = "deadcode -json ."
+	}
+	printIt("running dead code analysis")
+	state, stdout, stderr := cmdCapture(a, directedCommand{command: command, dir: WorkingDir()})
+	if !state {
+		printIt(strings.TrimSpace(stdout + "\n" + stderr))
+		return nil, false
+	}
+	return parseDeadcodePackages(stdout)
+}
+
+// FindingsOf returns the findings most recently reported by the named tool
+// ("gocritic", "nilaway", "deadcode", "gofix" or "govulncheck") when run by
+// Lint, NilAway, Deadcode, GoFix or VulnerabilityCheck; use len() to count
+// them
+func FindingsOf(tool string) Findings {
+	return reportedFindings[tool]
+}
+
+// reportFindings records the tool's findings, prints them, sorted and without
+// duplicates, in the format selected by the -findingsformat flag, and merges
+// them into the SARIF file named by the -sarif flag, if any
+func reportFindings(tool string, findings Findings) bool {
+	findings = findings.Deduplicate().Sorted()
+	reportedFindings[tool] = findings
+	rendered, ok := findings.Render(*FindingsFormatFlag)
+	if !ok {
+		return false
+	}
+	if rendered != "" {
+		printIt(rendered)
+	}
+	return *SARIFFlag == "" || WriteSARIF(*SARIFFlag, []string{tool}, findings)
+}
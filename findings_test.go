@@ -0,0 +1,500 @@
+package tools_build
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/goyek/goyek/v3"
+	"github.com/goyek/x/cmd"
+)
+
+// Copyright © 2026 Marc Johnson (marc.johnson27591@gmail.com)
+
+func TestFinding_String(t *testing.T) {
+	tests := map[string]struct {
+		finding Finding
+		want    string
+	}{
+		"full position": {
+			finding: Finding{Tool: "gocritic", File: "a.go", Line: 3, Column: 4, Rule: "hugeParam", Message: "too big"},
+			want:    "a.go:3:4: hugeParam: too big",
+		},
+		"line only": {
+			finding: Finding{Tool: "gocritic", File: "a.go", Line: 3, Rule: "hugeParam", Message: "too big"},
+			want:    "a.go:3: hugeParam: too big",
+		},
+		"file only": {
+			finding: Finding{Tool: "gocritic", File: "a.go", Rule: "hugeParam", Message: "too big"},
+			want:    "a.go: hugeParam: too big",
+		},
+		"no location": {
+			finding: Finding{Tool: "govulncheck", Rule: "GO-1", Message: "vulnerable"},
+			want:    "govulncheck: GO-1: vulnerable",
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			if got := tt.finding.String(); got != tt.want {
+				t.Errorf("String() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+var sampleFindings = Findings{
+	{Tool: "nilaway", File: "b/b.go", Line: 2, Column: 1, Rule: "nilaway", Message: "nil", Severity: SeverityError},
+	{Tool: "gocritic", File: "a.go", Line: 9, Column: 1, Rule: "dupImport", Message: "dup | import", Severity: SeverityWarning},
+	{Tool: "gocritic", File: "a.go", Line: 3, Column: 4, Rule: "hugeParam", Message: "too big", Severity: SeverityWarning},
+	{Tool: "govulncheck", Rule: "GO-1", Message: "vulnerable", Severity: SeverityWarning},
+	{Tool: "gocritic", File: "a.go", Line: 3, Column: 4, Rule: "hugeParam", Message: "too big", Severity: SeverityWarning},
+}
+
+func TestFindings_counts(t *testing.T) {
+	if got, want := sampleFindings.CountByTool(), map[string]int{"gocritic": 3, "govulncheck": 1, "nilaway": 1}; !reflect.DeepEqual(got, want) {
+		t.Errorf("CountByTool() = %v, want %v", got, want)
+	}
+	if got, want := sampleFindings.CountBySeverity(), map[string]int{SeverityError: 1, SeverityWarning: 4}; !reflect.DeepEqual(got, want) {
+		t.Errorf("CountBySeverity() = %v, want %v", got, want)
+	}
+}
+
+func TestFindings_paths(t *testing.T) {
+	tests := map[string]struct {
+		patterns      []string
+		wantIn        int
+		wantExcluding int
+	}{
+		"no patterns":  {wantIn: 0, wantExcluding: 5},
+		"file":         {patterns: []string{"a.go"}, wantIn: 3, wantExcluding: 2},
+		"directory":    {patterns: []string{"b/..."}, wantIn: 1, wantExcluding: 4},
+		"not a prefix": {patterns: []string{"b"}, wantIn: 0, wantExcluding: 5},
+		"both":         {patterns: []string{"a.go", "b/..."}, wantIn: 4, wantExcluding: 1},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			if got := sampleFindings.InPaths(tt.patterns...); len(got) != tt.wantIn {
+				t.Errorf("InPaths() = %v, want %d findings", got, tt.wantIn)
+			}
+			if got := sampleFindings.ExcludingPaths(tt.patterns...); len(got) != tt.wantExcluding {
+				t.Errorf("ExcludingPaths() = %v, want %d findings", got, tt.wantExcluding)
+			}
+		})
+	}
+}
+
+func TestFindings_Deduplicate_Sorted(t *testing.T) {
+	want := Findings{sampleFindings[3], sampleFindings[2], sampleFindings[1], sampleFindings[0]}
+	if got := sampleFindings.Deduplicate().Sorted(); !reflect.DeepEqual(got, want) {
+		t.Errorf("Deduplicate().Sorted() = %v, want %v", got, want)
+	}
+	if sampleFindings[0].Tool != "nilaway" {
+		t.Errorf("Sorted() modified the original findings")
+	}
+}
+
+func TestFindings_Render(t *testing.T) {
+	findings := Findings{sampleFindings[1], sampleFindings[3]}
+	tests := map[string]struct {
+		findings Findings
+		format   string
+		want     string
+		wantOk   bool
+	}{
+		"bad format": {findings: findings, format: "xml", wantOk: false},
+		"text": {
+			findings: findings,
+			format:   FindingsText,
+			want:     "a.go:9:1: dupImport: dup | import\ngovulncheck: GO-1: vulnerable",
+			wantOk:   true,
+		},
+		"empty text": {format: FindingsText, want: "", wantOk: true},
+		"json": {
+			findings: Findings{sampleFindings[3]},
+			format:   FindingsJSON,
+			want: "[\n" +
+				"  {\n" +
+				"    \"tool\": \"govulncheck\",\n" +
+				"    \"rule\": \"GO-1\",\n" +
+				"    \"message\": \"vulnerable\",\n" +
+				"    \"severity\": \"warning\"\n" +
+				"  }\n" +
+				"]",
+			wantOk: true,
+		},
+		"empty json": {format: FindingsJSON, want: "[]", wantOk: true},
+		"markdown": {
+			findings: findings,
+			format:   FindingsMarkdown,
+			want: "| File | Line | Column | Tool | Rule | Severity | Message |\n" +
+				"| --- | ---: | ---: | --- | --- | --- | --- |\n" +
+				"| a.go | 9 | 1 | gocritic | dupImport | warning | dup \\| import |\n" +
+				"| - | - | - | govulncheck | GO-1 | warning | vulnerable |",
+			wantOk: true,
+		},
+		"empty markdown": {format: FindingsMarkdown, want: "no findings", wantOk: true},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, gotOk := tt.findings.Render(tt.format)
+			if got != tt.want {
+				t.Errorf("Render() = %q, want %q", got, tt.want)
+			}
+			if gotOk != tt.wantOk {
+				t.Errorf("Render() ok = %v, want %v", gotOk, tt.wantOk)
+			}
+		})
+	}
+}
+
+func Test_splitPosition(t *testing.T) {
+	tests := map[string]struct {
+		position   string
+		wantFile   string
+		wantLine   int
+		wantColumn int
+	}{
+		"full":       {position: "/a/b.go:12:5", wantFile: "/a/b.go", wantLine: 12, wantColumn: 5},
+		"line only":  {position: "b.go:12", wantFile: "b.go", wantLine: 12},
+		"none":       {position: "b.go", wantFile: "b.go"},
+		"drive":      {position: `C:\a\b.go:1:2`, wantFile: `C:\a\b.go`, wantLine: 1, wantColumn: 2},
+		"not number": {position: "b.go:x", wantFile: "b.go:x"},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			gotFile, gotLine, gotColumn := splitPosition(tt.position)
+			if gotFile != tt.wantFile || gotLine != tt.wantLine || gotColumn != tt.wantColumn {
+				t.Errorf("splitPosition() = %q, %d, %d, want %q, %d, %d", gotFile, gotLine, gotColumn,
+					tt.wantFile, tt.wantLine, tt.wantColumn)
+			}
+		})
+	}
+}
+
+func Test_parseGocriticOutput(t *testing.T) {
+	output := "" +
+		"./tasks.go:12:3: hugeParam: options is heavy (80 bytes); consider passing it by pointer\n" +
+		"sub/a.go:4:1: commentFormatting: put a space between `//` and comment text\n" +
+		"exit status 1\n"
+	want := []Finding{
+		{
+			Tool: "gocritic", File: "tasks.go", Line: 12, Column: 3, Rule: "hugeParam",
+			Message: "options is heavy (80 bytes); consider passing it by pointer", Severity: SeverityWarning,
+		},
+		{
+			Tool: "gocritic", File: "sub/a.go", Line: 4, Column: 1, Rule: "commentFormatting",
+			Message: "put a space between `//` and comment text", Severity: SeverityWarning,
+		},
+	}
+	if got := parseGocriticOutput(output); !reflect.DeepEqual(got, want) {
+		t.Errorf("parseGocriticOutput() = %v, want %v", got, want)
+	}
+}
+
+func Test_parseAnalysisJSON(t *testing.T) {
+	tests := map[string]struct {
+		output string
+		want   []Finding
+		wantOk bool
+	}{
+		"bad output": {
+			output: "{",
+			want:   nil,
+			wantOk: false,
+		},
+		"nothing": {
+			output: "",
+			want:   []Finding{},
+			wantOk: true,
+		},
+		"diagnostics": {
+			output: `{"example.com/m": {"nilaway": [{"posn": "a.go:10:5", "message": "nil dereference"}]}}
+{"example.com/m/b": {"nilaway": [{"posn": "b/b.go:3:1", "message": "nil return"}]}}`,
+			want: []Finding{
+				{Tool: "nilaway", File: "a.go", Line: 10, Column: 5, Rule: "nilaway", Message: "nil dereference", Severity: SeverityError},
+				{Tool: "nilaway", File: "b/b.go", Line: 3, Column: 1, Rule: "nilaway", Message: "nil return", Severity: SeverityError},
+			},
+			wantOk: true,
+		},
+		"analyzer error": {
+			output: `{"example.com/m": {"nilaway": {"error": "type checking failed"}}}`,
+			want:   []Finding{},
+			wantOk: false,
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, gotOk := parseAnalysisJSON("nilaway", SeverityError, tt.output)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parseAnalysisJSON() got = %v, want %v", got, tt.want)
+			}
+			if gotOk != tt.wantOk {
+				t.Errorf("parseAnalysisJSON() gotOk = %v, want %v", gotOk, tt.wantOk)
+			}
+		})
+	}
+}
+
+func Test_parseDeadcodeJSON(t *testing.T) {
+	tests := map[string]struct {
+		output string
+		want   []Finding
+		wantOk bool
+	}{
+		"bad output": {
+			output: "[",
+			want:   nil,
+			wantOk: false,
+		},
+		"nothing": {
+			output: "",
+			want:   []Finding{},
+			wantOk: true,
+		},
+		"unreachable": {
+			output: `[{"Name": "m", "Path": "example.com/m", "Funcs": [
+				{"Name": "unused", "Position": {"File": "a.go", "Line": 7, "Col": 6}, "Generated": false}]}]`,
+			want: []Finding{
+				{
+					Tool: "deadcode", File: "a.go", Line: 7, Column: 6, Rule: "unreachable-func",
+					Message: "example.com/m.unused is unreachable", Severity: SeverityWarning,
+				},
+			},
+			wantOk: true,
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, gotOk := parseDeadcodeJSON(tt.output)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parseDeadcodeJSON() got = %v, want %v", got, tt.want)
+			}
+			if gotOk != tt.wantOk {
+				t.Errorf("parseDeadcodeJSON() gotOk = %v, want %v", gotOk, tt.wantOk)
+			}
+		})
+	}
+}
+
+func Test_vulnerabilityFindings(t *testing.T) {
+	vulnerabilities := []Vulnerability{
+		{
+			ID: "GO-1", Summary: "Crash", Module: "example.com/a", Version: "v1.0.0", FixedVersion: "v1.0.1",
+			Called: true, Symbol: "example.com/a.Parse", File: "main.go", Line: 3, Column: 2,
+		},
+		{ID: "GO-2", Module: "example.com/b", Version: "v0.1.0"},
+	}
+	want := []Finding{
+		{
+			Tool: "govulncheck", File: "main.go", Line: 3, Column: 2, Rule: "GO-1",
+			Message:  "Crash: example.com/a@v1.0.0 is vulnerable (fixed in v1.0.1); example.com/a.Parse is called",
+			Severity: SeverityError,
+		},
+		{
+			Tool: "govulncheck", Rule: "GO-2", Message: "example.com/b@v0.1.0 is vulnerable (no fix is available)",
+			Severity: SeverityWarning,
+		},
+	}
+	if got := vulnerabilityFindings(vulnerabilities); !reflect.DeepEqual(got, want) {
+		t.Errorf("vulnerabilityFindings() = %v, want %v", got, want)
+	}
+}
+
+func TestAnalysisFindings(t *testing.T) {
+	originalCachedWorkingDir := CachedWorkingDir
+	originalExecFn := ExecFn
+	originalCmdCapture := cmdCapture
+	originalNoTestFlag := NoTestFlag
+	defer func() {
+		CachedWorkingDir = originalCachedWorkingDir
+		ExecFn = originalExecFn
+		cmdCapture = originalCmdCapture
+		NoTestFlag = originalNoTestFlag
+	}()
+	CachedWorkingDir = "work"
+	tests := map[string]struct {
+		run             func(*goyek.A) (Findings, bool)
+		noTest          bool
+		installSucceeds bool
+		state           bool
+		stdout          string
+		wantCommand     string
+		wantCount       int
+		wantOk          bool
+	}{
+		"lint install fails": {
+			run:    LintFindings,
+			wantOk: false,
+		},
+		"lint cannot run": {
+			run:             LintFindings,
+			installSucceeds: true,
+			state:           false,
+			stdout:          "no Go files",
+			wantCommand:     "gocritic check -enableAll ./...",
+			wantOk:          false,
+		},
+		"lint finds problems": {
+			run:             LintFindings,
+			installSucceeds: true,
+			state:           false,
+			stdout:          "a.go:1:1: dupImport: duplicate import\n",
+			wantCommand:     "gocritic check -enableAll ./...",
+			wantCount:       1,
+			wantOk:          true,
+		},
+		"nilaway fails": {
+			run:             NilAwayFindings,
+			installSucceeds: true,
+			state:           false,
+			wantCommand:     "nilaway -json ./...",
+			wantOk:          false,
+		},
+		"nilaway succeeds": {
+			run:             NilAwayFindings,
+			installSucceeds: true,
+			state:           true,
+			stdout:          `{"example.com/m": {"nilaway": [{"posn": "a.go:10:5", "message": "nil dereference"}]}}`,
+			wantCommand:     "nilaway -json ./...",
+			wantCount:       1,
+			wantOk:          true,
+		},
+		"deadcode fails": {
+			run:             DeadcodeFindings,
+			installSucceeds: true,
+			state:           false,
+			wantCommand:     "deadcode -json -test .",
+			wantOk:          false,
+		},
+		"deadcode succeeds without tests": {
+			run:             DeadcodeFindings,
+			noTest:          true,
+			installSucceeds: true,
+			state:           true,
+			stdout:          "[]",
+			wantCommand:     "deadcode -json .",
+			wantOk:          true,
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			NoTestFlag = &tt.noTest
+			ExecFn = func(_ *goyek.A, _ string, _ ...cmd.Option) bool {
+				return tt.installSucceeds
+			}
+			gotCommand := ""
+			cmdCapture = func(_ *goyek.A, dC directedCommand) (bool, string, string) {
+				gotCommand = dC.command
+				return tt.state, tt.stdout, ""
+			}
+			got, gotOk := tt.run(nil)
+			if len(got) != tt.wantCount {
+				t.Errorf("findings = %v, want %d findings", got, tt.wantCount)
+			}
+			if gotOk != tt.wantOk {
+				t.Errorf("ok = %v, want %v", gotOk, tt.wantOk)
+			}
+			if gotCommand != tt.wantCommand {
+				t.Errorf("command = %q, want %q", gotCommand, tt.wantCommand)
+			}
+		})
+	}
+}
+
+func Test_parseGoFixDiff(t *testing.T) {
+	tests := map[string]struct {
+		diff string
+		want []Finding
+	}{
+		"no changes": {want: []Finding{}},
+		"no hunks": {
+			diff: "--- dir/file.go (old)\n+++ dir/file.go (new)\n-\told\n+\tnew\n",
+			want: []Finding{
+				{Tool: "gofix", File: "dir/file.go", Rule: "gofix", Message: "go fix would change this file", Severity: SeverityNote},
+			},
+		},
+		"hunks": {
+			diff: "--- a/a.go\n+++ b/a.go\n@@ -3,2 +3,3 @@\n x\n-y\n+z\n+w\n@@ -20 +21 @@ func f() {\n-a\n+b\n" +
+				"--- b/c.go (old)\n+++ b/c.go (new)\n@@ -1,0 +1,1 @@\n+c\n",
+			want: []Finding{
+				{Tool: "gofix", File: "a.go", Line: 3, Rule: "gofix", Message: "go fix would change lines 3-5", Severity: SeverityNote},
+				{Tool: "gofix", File: "a.go", Line: 21, Rule: "gofix", Message: "go fix would change lines 21-21", Severity: SeverityNote},
+				{Tool: "gofix", File: "b/c.go", Line: 1, Rule: "gofix", Message: "go fix would change lines 1-1", Severity: SeverityNote},
+			},
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			if got := parseGoFixDiff(tt.diff); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parseGoFixDiff() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGoFixFindings(t *testing.T) {
+	originalCmdOutput := cmdOutput
+	defer func() {
+		cmdOutput = originalCmdOutput
+	}()
+	tests := map[string]struct {
+		state     bool
+		output    string
+		wantCount int
+		wantOk    bool
+	}{
+		"go fix fails":   {state: false, wantOk: false},
+		"go fix changes": {state: true, output: "--- a.go (old)\n+++ a.go (new)\n@@ -1 +1 @@\n-a\n+b\n", wantCount: 1, wantOk: true},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			gotCommand := ""
+			cmdOutput = func(_ *goyek.A, command string) (bool, string) {
+				gotCommand = command
+				return tt.state, tt.output
+			}
+			got, gotOk := GoFixFindings(nil)
+			if len(got) != tt.wantCount {
+				t.Errorf("GoFixFindings() = %v, want %d findings", got, tt.wantCount)
+			}
+			if gotOk != tt.wantOk {
+				t.Errorf("GoFixFindings() ok = %v, want %v", gotOk, tt.wantOk)
+			}
+			if gotCommand != "go fix -diff ./..." {
+				t.Errorf("GoFixFindings() command = %q", gotCommand)
+			}
+		})
+	}
+}
+
+func Test_reportFindings(t *testing.T) {
+	originalFindingsFormatFlag := FindingsFormatFlag
+	originalSARIFFlag := SARIFFlag
+	defer func() {
+		FindingsFormatFlag = originalFindingsFormatFlag
+		SARIFFlag = originalSARIFFlag
+		delete(reportedFindings, "test")
+	}()
+	noSARIF := ""
+	SARIFFlag = &noSARIF
+	tests := map[string]struct {
+		format string
+		want   bool
+		wantN  int
+	}{
+		"bad format": {format: "xml", want: false, wantN: 4},
+		"markdown":   {format: FindingsMarkdown, want: true, wantN: 4},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			delete(reportedFindings, "test")
+			format := tt.format
+			FindingsFormatFlag = &format
+			if got := reportFindings("test", sampleFindings); got != tt.want {
+				t.Errorf("reportFindings() = %v, want %v", got, tt.want)
+			}
+			if got := FindingsOf("test"); len(got) != tt.wantN {
+				t.Errorf("FindingsOf() = %v, want %d findings", got, tt.wantN)
+			}
+		})
+	}
+}
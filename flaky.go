@@ -0,0 +1,138 @@
+package tools_build
+
+import (
+	"fmt"
+	"maps"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/goyek/goyek/v3"
+)
+
+// Copyright © 2026 Marc Johnson (marc.johnson27591@gmail.com)
+
+// flakyTest records a test that failed, but then passed when it was rerun
+type flakyTest struct {
+	pkg      string
+	name     string
+	attempts int
+}
+
+// failedTests returns the names of the failed top-level tests in each failed
+// package; a failed subtest is represented by its top-level test. A failed
+// package with no failed tests (one that failed to build, for example) maps to
+// an empty slice
+func (tr *testResults) failedTests() map[string][]string {
+	failed := map[string][]string{}
+	for _, pkg := range tr.packages {
+		if pkg.outcome != "fail" {
+			continue
+		}
+		names := make([]string, 0)
+		seen := map[string]bool{}
+		for _, test := range pkg.tests {
+			if test.outcome != "fail" {
+				continue
+			}
+			name, _, _ := strings.Cut(test.name, "/")
+			if !seen[name] {
+				seen[name] = true
+				names = append(names, name)
+			}
+		}
+		failed[pkg.name] = names
+	}
+	return failed
+}
+
+// merge replaces the package and test outcomes with those reported by a rerun
+func (tr *testResults) merge(rerun *testResults) {
+	for _, rerunPkg := range rerun.packages {
+		pkg := tr.pkg(rerunPkg.name)
+		pkg.outcome = rerunPkg.outcome
+		pkg.elapsed += rerunPkg.elapsed
+		pkg.output = rerunPkg.output
+		for _, rerunTest := range rerunPkg.tests {
+			test := pkg.test(rerunTest.name)
+			test.outcome = rerunTest.outcome
+			test.elapsed = rerunTest.elapsed
+			test.output = rerunTest.output
+		}
+	}
+	tr.lines = append(tr.lines, rerun.lines...)
+}
+
+// retryFailedTests reruns the failed tests, one package at a time, selecting
+// them by their exact names, until they pass or options.Retries attempts have
+// been made. The results are merged into the original results. Returns true if
+// all the failed tests eventually passed; the tests that needed retries are
+// reported as flaky. A run that failed without any failing package is not
+// retried
+func retryFailedTests(a *goyek.A, options TestOptions, results *testResults) bool {
+	originallyFailed := results.failedTests()
+	if len(originallyFailed) == 0 {
+		// the go command itself failed, such as for an invalid flag value
+		fmt.Fprintln(os.Stderr, "the unit tests failed without a failing package; they will not be retried")
+		return false
+	}
+	for pkg, names := range originallyFailed {
+		if len(names) == 0 {
+			fmt.Fprintf(os.Stderr, "package %q failed without a failing test; it will not be retried\n", pkg)
+			return false
+		}
+	}
+	attempts := map[string]int{}
+	for attempt := 1; attempt <= options.Retries; attempt++ {
+		failed := results.failedTests()
+		if len(failed) == 0 {
+			break
+		}
+		for _, pkg := range slices.Sorted(maps.Keys(failed)) {
+			if len(failed[pkg]) == 0 {
+				continue
+			}
+			printIt(fmt.Sprintf("retry %d of %d: rerunning %s in %s", attempt, options.Retries, strings.Join(failed[pkg], ", "), pkg))
+			for _, name := range failed[pkg] {
+				attempts[pkg+" "+name] = attempt
+			}
+			rerunOptions := options
+			rerunOptions.Run = "^(" + strings.Join(failed[pkg], "|") + ")$"
+			rerunOptions.Packages = []string{pkg}
+			rerunOptions.FailFast = false
+			// a rerun's coverage profile would replace the complete profile
+			// written by the original run
+			rerunOptions.CoverProfile = ""
+			rerunOptions.Coverage = nil
+			_, rerun := runJSONTests(a, rerunOptions.command(true))
+			results.merge(rerun)
+		}
+	}
+	flaky := make([]flakyTest, 0)
+	for _, pkg := range slices.Sorted(maps.Keys(originallyFailed)) {
+		for _, name := range originallyFailed[pkg] {
+			if test := results.index[pkg].testIndex[name]; test.outcome == "pass" {
+				flaky = append(flaky, flakyTest{pkg: pkg, name: name, attempts: attempts[pkg+" "+name]})
+			}
+		}
+	}
+	state := len(results.failedTests()) == 0
+	report := flakyReport(flaky)
+	printIt(report)
+	if options.FlakyReport != "" {
+		state = writeWorkingFile(options.FlakyReport, []byte(report+"\n")) && state
+	}
+	return state
+}
+
+// flakyReport describes the tests that needed retries in order to pass
+func flakyReport(flaky []flakyTest) string {
+	if len(flaky) == 0 {
+		return "flaky tests: none"
+	}
+	lines := []string{fmt.Sprintf("flaky tests: %d", len(flaky))}
+	for _, test := range flaky {
+		lines = append(lines, fmt.Sprintf("\t%s\t%s\tpassed on retry %d", test.pkg, test.name, test.attempts))
+	}
+	return strings.Join(lines, "\n")
+}
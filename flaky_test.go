@@ -0,0 +1,169 @@
+package tools_build
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/goyek/goyek/v3"
+	"github.com/spf13/afero"
+)
+
+// Copyright © 2026 Marc Johnson (marc.johnson27591@gmail.com)
+
+func Test_testResults_failedTests(t *testing.T) {
+	events := "" +
+		`{"Action":"fail","Package":"p1","Test":"TestA/sub1"}` + "\n" +
+		`{"Action":"fail","Package":"p1","Test":"TestA"}` + "\n" +
+		`{"Action":"pass","Package":"p1","Test":"TestB"}` + "\n" +
+		`{"Action":"fail","Package":"p1","Test":"TestC"}` + "\n" +
+		`{"Action":"fail","Package":"p1"}` + "\n" +
+		`{"Action":"pass","Package":"p2","Test":"TestD"}` + "\n" +
+		`{"Action":"pass","Package":"p2"}` + "\n" +
+		`{"Action":"fail","Package":"p3"}` + "\n"
+	want := map[string][]string{
+		"p1": {"TestA", "TestC"},
+		"p3": {},
+	}
+	if got := parseTestEvents(events).failedTests(); !reflect.DeepEqual(got, want) {
+		t.Errorf("failedTests() = %v, want %v", got, want)
+	}
+}
+
+func Test_testResults_merge(t *testing.T) {
+	results := parseTestEvents("" +
+		`{"Action":"pass","Package":"p","Test":"TestA","Elapsed":1}` + "\n" +
+		`{"Action":"fail","Package":"p","Test":"TestB","Elapsed":2}` + "\n" +
+		`{"Action":"fail","Package":"p","Elapsed":3}` + "\n")
+	results.merge(parseTestEvents("" +
+		`{"Action":"pass","Package":"p","Test":"TestB","Elapsed":0.5}` + "\n" +
+		`{"Action":"pass","Package":"p","Elapsed":1}` + "\n"))
+	pkg := results.index["p"]
+	if pkg.outcome != "pass" || pkg.elapsed != 4 {
+		t.Errorf("merge() got package outcome %q elapsed %v", pkg.outcome, pkg.elapsed)
+	}
+	if got := pkg.testIndex["TestA"]; got.outcome != "pass" || got.elapsed != 1 {
+		t.Errorf("merge() got TestA %+v", got)
+	}
+	if got := pkg.testIndex["TestB"]; got.outcome != "pass" || got.elapsed != 0.5 {
+		t.Errorf("merge() got TestB %+v", got)
+	}
+}
+
+func Test_retryFailedTests(t *testing.T) {
+	originalCmdCapture := cmdCapture
+	originalCachedWorkingDir := CachedWorkingDir
+	originalBuildFS := BuildFS
+	defer func() {
+		cmdCapture = originalCmdCapture
+		CachedWorkingDir = originalCachedWorkingDir
+		BuildFS = originalBuildFS
+	}()
+	CachedWorkingDir = "work"
+	failedRun := "" +
+		`{"Action":"fail","Package":"p","Test":"TestFlaky"}` + "\n" +
+		`{"Action":"fail","Package":"p"}` + "\n"
+	passedRun := "" +
+		`{"Action":"pass","Package":"p","Test":"TestFlaky"}` + "\n" +
+		`{"Action":"pass","Package":"p"}` + "\n"
+	tests := map[string]struct {
+		original     string
+		retries      int
+		passOnRetry  int
+		flakyReport  string
+		wantCommands []string
+		wantReport   string
+		want         bool
+	}{
+		"build failure": {
+			original:     `{"Action":"fail","Package":"p"}`,
+			retries:      3,
+			wantCommands: []string{},
+			want:         false,
+		},
+		"go command failure": {
+			original:     "",
+			retries:      3,
+			wantCommands: []string{},
+			want:         false,
+		},
+		"passes on second retry": {
+			original:    failedRun,
+			retries:     3,
+			passOnRetry: 2,
+			flakyReport: "flaky.txt",
+			wantCommands: []string{
+				"go test -json -cover -run='^(TestFlaky)$' p",
+				"go test -json -cover -run='^(TestFlaky)$' p",
+			},
+			wantReport: "flaky tests: 1\n\tp\tTestFlaky\tpassed on retry 2\n",
+			want:       true,
+		},
+		"never passes": {
+			original:    failedRun,
+			retries:     2,
+			passOnRetry: 3,
+			wantCommands: []string{
+				"go test -json -cover -run='^(TestFlaky)$' p",
+				"go test -json -cover -run='^(TestFlaky)$' p",
+			},
+			want: false,
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			BuildFS = afero.NewMemMapFs()
+			gotCommands := make([]string, 0)
+			cmdCapture = func(_ *goyek.A, dC directedCommand) (bool, string, string) {
+				gotCommands = append(gotCommands, dC.command)
+				if len(gotCommands) >= tt.passOnRetry {
+					return true, passedRun, ""
+				}
+				return false, failedRun, ""
+			}
+			options := TestOptions{Retries: tt.retries, FailFast: true, FlakyReport: tt.flakyReport}
+			if got := retryFailedTests(nil, options, parseTestEvents(tt.original)); got != tt.want {
+				t.Errorf("retryFailedTests() = %v, want %v", got, tt.want)
+			}
+			if !reflect.DeepEqual(gotCommands, tt.wantCommands) {
+				t.Errorf("retryFailedTests() commands = %v, want %v", gotCommands, tt.wantCommands)
+			}
+			if tt.flakyReport != "" {
+				content, _ := afero.ReadFile(BuildFS, "work/"+tt.flakyReport)
+				if string(content) != tt.wantReport {
+					t.Errorf("retryFailedTests() report = %q, want %q", content, tt.wantReport)
+				}
+			}
+		})
+	}
+}
+
+func Test_flakyReport(t *testing.T) {
+	tests := map[string]struct {
+		flaky []flakyTest
+		want  string
+	}{
+		"none": {
+			flaky: nil,
+			want:  "flaky tests: none",
+		},
+		"several": {
+			flaky: []flakyTest{
+				{pkg: "p1", name: "TestA", attempts: 1},
+				{pkg: "p2", name: "TestB", attempts: 3},
+			},
+			want: strings.Join([]string{
+				"flaky tests: 2",
+				"\tp1\tTestA\tpassed on retry 1",
+				"\tp2\tTestB\tpassed on retry 3",
+			}, "\n"),
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			if got := flakyReport(tt.flaky); got != tt.want {
+				t.Errorf("flakyReport() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
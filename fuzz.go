@@ -0,0 +1,207 @@
+package tools_build
+
+import (
+	"flag"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/goyek/goyek/v3"
+	"github.com/spf13/afero"
+)
+
+// Copyright © 2026 Marc Johnson (marc.johnson27591@gmail.com)
+
+// DefaultFuzzTime is the length of time each fuzz target runs when no other
+// time is specified
+const DefaultFuzzTime = "10s"
+
+// FuzzTimeFlag is a flag that sets how long each fuzz target runs
+var FuzzTimeFlag = flag.String(
+	"fuzztime",
+	DefaultFuzzTime,
+	"set to the length of time, such as 30s or 1000x, that each fuzz target runs")
+
+// fuzzTarget identifies a fuzz test
+type fuzzTarget struct {
+	// dir is the directory, relative to WorkingDir(), of the package that
+	// contains the fuzz test
+	dir string
+	// name is the fuzz test's function name
+	name string
+}
+
+// corpusDir returns the directory, relative to WorkingDir(), where 'go test
+// -fuzz' writes the inputs that make the fuzz test fail
+func (ft fuzzTarget) corpusDir() string {
+	return filepath.Join(ft.dir, "testdata", "fuzz", ft.name)
+}
+
+// Fuzz finds every fuzz test (a function named Fuzz* that takes a *testing.F)
+// in the working directory tree and runs each one, in turn, for fuzzTime (any
+// value accepted by 'go test -fuzztime'). Any new failing inputs written to
+// testdata/fuzz are reported. Returns false if any fuzz test fails or writes
+// a new failing input
+func Fuzz(a *goyek.A, fuzzTime string) bool {
+	if fuzzTime == "" || strings.HasPrefix(fuzzTime, "-") {
+		fmt.Fprintf(os.Stderr, "cannot accept %q as a valid fuzz time\n", fuzzTime)
+		return false
+	}
+	targets, ok := findFuzzTargets()
+	if !ok {
+		return false
+	}
+	if len(targets) == 0 {
+		printIt("no fuzz tests found")
+		return true
+	}
+	status := true
+	crashers := make([]string, 0)
+	for _, target := range targets {
+		existing := corpusFiles(target.corpusDir())
+		path := filepath.Join(WorkingDir(), target.dir)
+		fmt.Printf("%q: fuzzing %s for %s\n", path, target.name, fuzzTime)
+		fuzzCommand := directedCommand{
+			command: fmt.Sprintf("go test -run=^$ -fuzz=^%s$ -fuzztime=%s .", target.name, quoteArg(fuzzTime)),
+			dir:     path,
+		}
+		if !fuzzCommand.execute(a) {
+			status = false
+		}
+		for _, file := range corpusFiles(target.corpusDir()) {
+			if !slices.Contains(existing, file) {
+				crashers = append(crashers, canonicalPath(filepath.Join(target.corpusDir(), file)))
+			}
+		}
+	}
+	if len(crashers) != 0 {
+		printIt("new failing fuzz inputs:\n\t" + strings.Join(crashers, "\n\t"))
+		status = false
+	}
+	return status
+}
+
+// findFuzzTargets parses the test files in each package directory in the
+// working directory tree, returning the fuzz tests they declare; directories
+// that the go command ignores (testdata, and those whose names begin with '.'
+// or '_') are skipped
+func findFuzzTargets() ([]fuzzTarget, bool) {
+	dirs, err := RelevantDirs(matchGoTest)
+	if err != nil {
+		return nil, false
+	}
+	targets := make([]fuzzTarget, 0)
+	for _, dir := range dirs {
+		if isIgnoredPackageDir(dir) {
+			continue
+		}
+		entries, _ := afero.ReadDir(BuildFS, filepath.Join(WorkingDir(), dir))
+		for _, entry := range entries {
+			if !IsRelevantFile(entry, matchGoTest) {
+				continue
+			}
+			fileName := filepath.Join(WorkingDir(), dir, entry.Name())
+			content, readErr := afero.ReadFile(BuildFS, fileName)
+			if readErr != nil {
+				fmt.Fprintf(os.Stderr, "error %v reading %q\n", readErr, fileName)
+				return nil, false
+			}
+			names, ok := fuzzTestNames(fileName, content)
+			if !ok {
+				return nil, false
+			}
+			for _, name := range names {
+				targets = append(targets, fuzzTarget{dir: dir, name: name})
+			}
+		}
+	}
+	return targets, true
+}
+
+// fuzzTestNames parses a test file, returning the names of the fuzz tests it
+// declares
+func fuzzTestNames(fileName string, content []byte) ([]string, bool) {
+	file, err := parser.ParseFile(token.NewFileSet(), fileName, content, parser.SkipObjectResolution)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "error %v parsing %q\n", err, fileName)
+		return nil, false
+	}
+	testingName := ""
+	for _, spec := range file.Imports {
+		if importPath, _ := strconv.Unquote(spec.Path.Value); importPath == "testing" {
+			testingName = "testing"
+			if spec.Name != nil {
+				testingName = spec.Name.Name
+			}
+		}
+	}
+	names := make([]string, 0)
+	if testingName == "" {
+		return names, true
+	}
+	for _, decl := range file.Decls {
+		function, isFunction := decl.(*ast.FuncDecl)
+		if !isFunction || function.Recv != nil || !isFuzzTestName(function.Name.Name) {
+			continue
+		}
+		params := function.Type.Params.List
+		if len(params) != 1 || len(params[0].Names) > 1 || function.Type.Results != nil {
+			continue
+		}
+		if isTestingF(params[0].Type, testingName) {
+			names = append(names, function.Name.Name)
+		}
+	}
+	return names, true
+}
+
+// isFuzzTestName applies the go command's rule: the name is 'Fuzz', or
+// 'Fuzz' followed by a character that is not a lower-case letter
+func isFuzzTestName(name string) bool {
+	suffix, found := strings.CutPrefix(name, "Fuzz")
+	if !found {
+		return false
+	}
+	return suffix == "" || suffix[0] < 'a' || suffix[0] > 'z'
+}
+
+func isTestingF(expr ast.Expr, testingName string) bool {
+	star, isStar := expr.(*ast.StarExpr)
+	if !isStar {
+		return false
+	}
+	selector, isSelector := star.X.(*ast.SelectorExpr)
+	if !isSelector || selector.Sel.Name != "F" {
+		return false
+	}
+	pkg, isIdent := selector.X.(*ast.Ident)
+	return isIdent && pkg.Name == testingName
+}
+
+func isIgnoredPackageDir(dir string) bool {
+	for element := range strings.SplitSeq(canonicalPath(dir), "/") {
+		if element == "testdata" || startsWith(element, ".") || startsWith(element, "_") {
+			return true
+		}
+	}
+	return false
+}
+
+// corpusFiles returns the names of the files in a fuzz corpus directory,
+// which is relative to WorkingDir()
+func corpusFiles(dir string) []string {
+	entries, _ := afero.ReadDir(BuildFS, filepath.Join(WorkingDir(), dir))
+	files := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		if entry.Mode().IsRegular() {
+			files = append(files, entry.Name())
+		}
+	}
+	return files
+}
@@ -0,0 +1,184 @@
+package tools_build
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/goyek/goyek/v3"
+	"github.com/goyek/x/cmd"
+	"github.com/spf13/afero"
+)
+
+// Copyright © 2026 Marc Johnson (marc.johnson27591@gmail.com)
+
+const fuzzTestSource = `package a
+
+import "testing"
+
+func FuzzParse(f *testing.F) {}
+
+func FuzzFormat(f *testing.F) {}
+
+func Fuzzy(f *testing.F) {}
+
+func TestParse(t *testing.T) {}
+`
+
+func TestFuzz(t *testing.T) {
+	originalBuildFS := BuildFS
+	originalCachedWorkingDir := CachedWorkingDir
+	originalExecFn := ExecFn
+	defer func() {
+		BuildFS = originalBuildFS
+		CachedWorkingDir = originalCachedWorkingDir
+		ExecFn = originalExecFn
+	}()
+	CachedWorkingDir = "work"
+	tests := map[string]struct {
+		fuzzTime     string
+		source       string
+		failTarget   string
+		crashTarget  string
+		wantCommands []string
+		want         bool
+	}{
+		"bad fuzz time": {
+			fuzzTime:     "-1s",
+			source:       fuzzTestSource,
+			wantCommands: []string{},
+			want:         false,
+		},
+		"unparseable test file": {
+			fuzzTime:     "10s",
+			source:       "package a\nfunc {",
+			wantCommands: []string{},
+			want:         false,
+		},
+		"no fuzz tests": {
+			fuzzTime:     "10s",
+			source:       "package a\n",
+			wantCommands: []string{},
+			want:         true,
+		},
+		"all pass": {
+			fuzzTime: "30s",
+			source:   fuzzTestSource,
+			wantCommands: []string{
+				"go test -run=^$ -fuzz=^FuzzParse$ -fuzztime=30s .",
+				"go test -run=^$ -fuzz=^FuzzFormat$ -fuzztime=30s .",
+			},
+			want: true,
+		},
+		"one fails": {
+			fuzzTime:   "10s",
+			source:     fuzzTestSource,
+			failTarget: "FuzzParse",
+			wantCommands: []string{
+				"go test -run=^$ -fuzz=^FuzzParse$ -fuzztime=10s .",
+				"go test -run=^$ -fuzz=^FuzzFormat$ -fuzztime=10s .",
+			},
+			want: false,
+		},
+		"crasher written": {
+			fuzzTime:    "10s",
+			source:      fuzzTestSource,
+			crashTarget: "FuzzFormat",
+			wantCommands: []string{
+				"go test -run=^$ -fuzz=^FuzzParse$ -fuzztime=10s .",
+				"go test -run=^$ -fuzz=^FuzzFormat$ -fuzztime=10s .",
+			},
+			want: false,
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			BuildFS = afero.NewMemMapFs()
+			_ = BuildFS.MkdirAll("work/a/testdata/fuzz/FuzzParse", dirMode)
+			_ = afero.WriteFile(BuildFS, "work/a/a_test.go", []byte(tt.source), fileMode)
+			_ = afero.WriteFile(BuildFS, "work/a/testdata/fuzz/FuzzParse/seed", []byte("go test fuzz v1\n"), fileMode)
+			_ = afero.WriteFile(BuildFS, "work/a/testdata/b_test.go", []byte("package b\n"), fileMode)
+			gotCommands := make([]string, 0)
+			ExecFn = func(_ *goyek.A, command string, _ ...cmd.Option) bool {
+				gotCommands = append(gotCommands, command)
+				if tt.crashTarget != "" && command == "go test -run=^$ -fuzz=^"+tt.crashTarget+"$ -fuzztime=10s ." {
+					_ = afero.WriteFile(BuildFS, "work/a/testdata/fuzz/"+tt.crashTarget+"/0123abcd", []byte("go test fuzz v1\n"), fileMode)
+				}
+				return tt.failTarget == "" || command != "go test -run=^$ -fuzz=^"+tt.failTarget+"$ -fuzztime=10s ."
+			}
+			if got := Fuzz(nil, tt.fuzzTime); got != tt.want {
+				t.Errorf("Fuzz() = %v, want %v", got, tt.want)
+			}
+			if !reflect.DeepEqual(gotCommands, tt.wantCommands) {
+				t.Errorf("Fuzz() commands = %v, want %v", gotCommands, tt.wantCommands)
+			}
+		})
+	}
+}
+
+func Test_fuzzTestNames(t *testing.T) {
+	tests := map[string]struct {
+		content string
+		want    []string
+		wantOK  bool
+	}{
+		"syntax error": {
+			content: "package a\nfunc {",
+			want:    nil,
+			wantOK:  false,
+		},
+		"no testing import": {
+			content: "package a\n\nfunc FuzzA(f *F) {}\n",
+			want:    []string{},
+			wantOK:  true,
+		},
+		"renamed import": {
+			content: "package a\n\nimport tst \"testing\"\n\nfunc FuzzA(f *tst.F) {}\n\nfunc FuzzB(f *testing.F) {}\n",
+			want:    []string{"FuzzA"},
+			wantOK:  true,
+		},
+		"variety": {
+			content: "package a\n\nimport \"testing\"\n\n" +
+				"func Fuzz(f *testing.F) {}\n" +
+				"func Fuzz_A(f *testing.F) {}\n" +
+				"func Fuzzer(f *testing.F) {}\n" +
+				"func FuzzB(f testing.F) {}\n" +
+				"func FuzzC(f *testing.T) {}\n" +
+				"func FuzzD(f *testing.F) error { return nil }\n" +
+				"func FuzzE(f, g *testing.F) {}\n" +
+				"func (x y) FuzzF(f *testing.F) {}\n",
+			want:   []string{"Fuzz", "Fuzz_A"},
+			wantOK: true,
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, gotOK := fuzzTestNames("a_test.go", []byte(tt.content))
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("fuzzTestNames() got = %v, want %v", got, tt.want)
+			}
+			if gotOK != tt.wantOK {
+				t.Errorf("fuzzTestNames() gotOK = %v, want %v", gotOK, tt.wantOK)
+			}
+		})
+	}
+}
+
+func Test_isIgnoredPackageDir(t *testing.T) {
+	tests := map[string]struct {
+		dir  string
+		want bool
+	}{
+		"top":        {dir: "", want: false},
+		"package":    {dir: "a/b", want: false},
+		"testdata":   {dir: "a/testdata/b", want: true},
+		"hidden":     {dir: ".git", want: true},
+		"underscore": {dir: "a/_old", want: true},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			if got := isIgnoredPackageDir(tt.dir); got != tt.want {
+				t.Errorf("isIgnoredPackageDir() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -6,6 +6,7 @@ require (
 	github.com/goyek/goyek/v3 v3.0.1
 	github.com/goyek/x v0.4.0
 	github.com/spf13/afero v1.15.0
+	golang.org/x/mod v0.41.0
 )
 
 require (
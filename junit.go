@@ -0,0 +1,257 @@
+package tools_build
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/goyek/goyek/v3"
+)
+
+// Copyright © 2026 Marc Johnson (marc.johnson27591@gmail.com)
+
+// testEvent is a single event emitted by 'go test -json'
+type testEvent struct {
+	Time    time.Time
+	Action  string
+	Package string
+	Test    string
+	Elapsed float64
+	Output  string
+}
+
+// testCaseResult accumulates the events for a single test
+type testCaseResult struct {
+	name    string
+	outcome string
+	elapsed float64
+	output  []string
+}
+
+// packageResult accumulates the events for a single package and its tests
+type packageResult struct {
+	name      string
+	outcome   string
+	elapsed   float64
+	start     time.Time
+	output    []string
+	tests     []*testCaseResult
+	testIndex map[string]*testCaseResult
+}
+
+// testResults holds the results of a 'go test -json' run, in the order in
+// which packages and tests were first reported
+type testResults struct {
+	packages []*packageResult
+	index    map[string]*packageResult
+	// lines holds the output of every event, in order
+	lines []string
+}
+
+func newTestResults() *testResults {
+	return &testResults{index: map[string]*packageResult{}}
+}
+
+// parseTestEvents parses the output of 'go test -json'; lines that are not
+// JSON test events are ignored
+func parseTestEvents(s string) *testResults {
+	results := newTestResults()
+	for line := range strings.SplitSeq(s, "\n") {
+		var event testEvent
+		if err := json.Unmarshal([]byte(strings.TrimSpace(line)), &event); err != nil {
+			continue
+		}
+		results.add(event)
+	}
+	return results
+}
+
+func (tr *testResults) add(event testEvent) {
+	if event.Output != "" {
+		tr.lines = append(tr.lines, event.Output)
+	}
+	if event.Package == "" {
+		return
+	}
+	pkg := tr.pkg(event.Package)
+	if event.Test == "" {
+		switch event.Action {
+		case "start":
+			pkg.start = event.Time
+		case "output":
+			pkg.output = append(pkg.output, event.Output)
+		case "pass", "fail", "skip":
+			pkg.outcome = event.Action
+			pkg.elapsed = event.Elapsed
+		}
+		return
+	}
+	test := pkg.test(event.Test)
+	switch event.Action {
+	case "output":
+		test.output = append(test.output, event.Output)
+	case "pass", "fail", "skip":
+		test.outcome = event.Action
+		test.elapsed = event.Elapsed
+	}
+}
+
+func (tr *testResults) pkg(name string) *packageResult {
+	pkg, found := tr.index[name]
+	if !found {
+		pkg = &packageResult{name: name, testIndex: map[string]*testCaseResult{}}
+		tr.index[name] = pkg
+		tr.packages = append(tr.packages, pkg)
+	}
+	return pkg
+}
+
+func (pr *packageResult) test(name string) *testCaseResult {
+	test, found := pr.testIndex[name]
+	if !found {
+		test = &testCaseResult{name: name}
+		pr.testIndex[name] = test
+		pr.tests = append(pr.tests, test)
+	}
+	return test
+}
+
+// runJSONTests runs a 'go test -json' command in the working directory,
+// displays its output as plain text, and returns its state and parsed results
+func runJSONTests(a *goyek.A, command string) (bool, *testResults) {
+	state, stdout, stderr := cmdCapture(a, directedCommand{command: command, dir: WorkingDir()})
+	results := parseTestEvents(stdout)
+	if output := EatTrailingEOL(results.output()); output != "" {
+		printIt(output)
+	}
+	if stderr != "" {
+		printIt(stderr)
+	}
+	return state, results
+}
+
+// output reassembles the plain text output of the test run
+func (tr *testResults) output() string {
+	return strings.Join(tr.lines, "")
+}
+
+type junitTestSuites struct {
+	XMLName  xml.Name         `xml:"testsuites"`
+	Tests    int              `xml:"tests,attr"`
+	Failures int              `xml:"failures,attr"`
+	Errors   int              `xml:"errors,attr"`
+	Skipped  int              `xml:"skipped,attr"`
+	Time     string           `xml:"time,attr"`
+	Suites   []junitTestSuite `xml:"testsuite"`
+}
+
+type junitTestSuite struct {
+	Name      string          `xml:"name,attr"`
+	Tests     int             `xml:"tests,attr"`
+	Failures  int             `xml:"failures,attr"`
+	Errors    int             `xml:"errors,attr"`
+	Skipped   int             `xml:"skipped,attr"`
+	Time      string          `xml:"time,attr"`
+	Timestamp string          `xml:"timestamp,attr,omitempty"`
+	Cases     []junitTestCase `xml:"testcase"`
+	SystemOut string          `xml:"system-out,omitempty"`
+}
+
+type junitTestCase struct {
+	ClassName string        `xml:"classname,attr"`
+	Name      string        `xml:"name,attr"`
+	Time      string        `xml:"time,attr"`
+	Failure   *junitMessage `xml:"failure,omitempty"`
+	Skipped   *junitMessage `xml:"skipped,omitempty"`
+}
+
+type junitMessage struct {
+	Message  string `xml:"message,attr"`
+	Contents string `xml:",chardata"`
+}
+
+func junitTime(seconds float64) string {
+	return fmt.Sprintf("%.3f", seconds)
+}
+
+// junitReport converts test results into the JUnit XML model. A package that
+// fails without any failing test (a build failure, for example) is counted as
+// an error, and its output is preserved in the suite's system-out element
+func junitReport(results *testResults) junitTestSuites {
+	report := junitTestSuites{}
+	var total float64
+	for _, pkg := range results.packages {
+		suite := junitTestSuite{
+			Name: pkg.name,
+			Time: junitTime(pkg.elapsed),
+		}
+		if !pkg.start.IsZero() {
+			suite.Timestamp = pkg.start.UTC().Format("2006-01-02T15:04:05")
+		}
+		for _, test := range pkg.tests {
+			testCase := junitTestCase{
+				ClassName: pkg.name,
+				Name:      test.name,
+				Time:      junitTime(test.elapsed),
+			}
+			switch test.outcome {
+			case "fail":
+				testCase.Failure = &junitMessage{Message: "Failed", Contents: strings.Join(test.output, "")}
+				suite.Failures++
+			case "skip":
+				testCase.Skipped = &junitMessage{Message: skipMessage(test.output), Contents: strings.Join(test.output, "")}
+				suite.Skipped++
+			}
+			suite.Tests++
+			suite.Cases = append(suite.Cases, testCase)
+		}
+		if pkg.outcome == "fail" && suite.Failures == 0 {
+			suite.Errors++
+		}
+		if pkg.outcome == "fail" {
+			suite.SystemOut = strings.Join(pkg.output, "")
+		}
+		report.Tests += suite.Tests
+		report.Failures += suite.Failures
+		report.Errors += suite.Errors
+		report.Skipped += suite.Skipped
+		total += pkg.elapsed
+		report.Suites = append(report.Suites, suite)
+	}
+	report.Time = junitTime(total)
+	return report
+}
+
+// skipMessage extracts the reason given for skipping a test, which 'go test'
+// reports in the last output line before the '--- SKIP' line
+func skipMessage(output []string) string {
+	message := ""
+	for _, line := range output {
+		switch {
+		case strings.Contains(line, "--- SKIP"):
+			if message != "" {
+				return message
+			}
+			return "Skipped"
+		case strings.HasPrefix(line, "=== "), strings.TrimSpace(line) == "":
+			// a frame line, such as '=== RUN', says nothing about the test
+		default:
+			message = strings.TrimSpace(line)
+		}
+	}
+	return "Skipped"
+}
+
+// writeJUnitReport writes the test results as a JUnit XML file; returns false
+// on failure
+func writeJUnitReport(fileName string, results *testResults) bool {
+	content, err := xml.MarshalIndent(junitReport(results), "", "  ")
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "error %v creating JUnit report\n", err)
+		return false
+	}
+	return writeWorkingFile(fileName, append([]byte(xml.Header), append(content, '\n')...))
+}
@@ -0,0 +1,171 @@
+package tools_build
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/spf13/afero"
+)
+
+// Copyright © 2026 Marc Johnson (marc.johnson27591@gmail.com)
+
+const sampleTestEvents = "" +
+	`{"Time":"2026-10-18T12:00:00Z","Action":"start","Package":"example.com/a"}` + "\n" +
+	`{"Action":"run","Package":"example.com/a","Test":"TestPass"}` + "\n" +
+	`{"Action":"output","Package":"example.com/a","Test":"TestPass","Output":"=== RUN   TestPass\n"}` + "\n" +
+	`{"Action":"output","Package":"example.com/a","Test":"TestPass","Output":"--- PASS: TestPass (0.10s)\n"}` + "\n" +
+	`{"Action":"pass","Package":"example.com/a","Test":"TestPass","Elapsed":0.1}` + "\n" +
+	`{"Action":"run","Package":"example.com/a","Test":"TestFail"}` + "\n" +
+	`{"Action":"output","Package":"example.com/a","Test":"TestFail","Output":"=== RUN   TestFail\n"}` + "\n" +
+	`{"Action":"output","Package":"example.com/a","Test":"TestFail","Output":"    a_test.go:12: oops\n"}` + "\n" +
+	`{"Action":"output","Package":"example.com/a","Test":"TestFail","Output":"--- FAIL: TestFail (0.20s)\n"}` + "\n" +
+	`{"Action":"fail","Package":"example.com/a","Test":"TestFail","Elapsed":0.2}` + "\n" +
+	`{"Action":"run","Package":"example.com/a","Test":"TestSkip"}` + "\n" +
+	`{"Action":"output","Package":"example.com/a","Test":"TestSkip","Output":"=== RUN   TestSkip\n"}` + "\n" +
+	`{"Action":"output","Package":"example.com/a","Test":"TestSkip","Output":"    a_test.go:20: not today\n"}` + "\n" +
+	`{"Action":"output","Package":"example.com/a","Test":"TestSkip","Output":"--- SKIP: TestSkip (0.00s)\n"}` + "\n" +
+	`{"Action":"skip","Package":"example.com/a","Test":"TestSkip","Elapsed":0}` + "\n" +
+	`{"Action":"output","Package":"example.com/a","Output":"FAIL\n"}` + "\n" +
+	`{"Action":"fail","Package":"example.com/a","Elapsed":0.35}` + "\n" +
+	"not a json line\n" +
+	`{"Action":"output","Package":"example.com/b","Output":"# example.com/b\n"}` + "\n" +
+	`{"Action":"output","Package":"example.com/b","Output":"FAIL\texample.com/b [build failed]\n"}` + "\n" +
+	`{"Action":"fail","Package":"example.com/b","Elapsed":0}` + "\n"
+
+func Test_parseTestEvents(t *testing.T) {
+	results := parseTestEvents(sampleTestEvents)
+	if got := len(results.packages); got != 2 {
+		t.Fatalf("parseTestEvents() got %d packages, want 2", got)
+	}
+	a := results.packages[0]
+	if a.name != "example.com/a" || a.outcome != "fail" || a.elapsed != 0.35 {
+		t.Errorf("parseTestEvents() got package %q outcome %q elapsed %v", a.name, a.outcome, a.elapsed)
+	}
+	if want := time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC); !a.start.Equal(want) {
+		t.Errorf("parseTestEvents() got start %v, want %v", a.start, want)
+	}
+	gotOutcomes := map[string]string{}
+	for _, test := range a.tests {
+		gotOutcomes[test.name] = test.outcome
+	}
+	wantOutcomes := map[string]string{"TestPass": "pass", "TestFail": "fail", "TestSkip": "skip"}
+	if !reflect.DeepEqual(gotOutcomes, wantOutcomes) {
+		t.Errorf("parseTestEvents() got outcomes %v, want %v", gotOutcomes, wantOutcomes)
+	}
+	if got := results.output(); !strings.HasPrefix(got, "=== RUN   TestPass\n--- PASS: TestPass (0.10s)\n") {
+		t.Errorf("parseTestEvents() got output %q", got)
+	}
+}
+
+func Test_junitReport(t *testing.T) {
+	report := junitReport(parseTestEvents(sampleTestEvents))
+	if report.Tests != 3 || report.Failures != 1 || report.Skipped != 1 || report.Errors != 1 {
+		t.Errorf("junitReport() got tests=%d failures=%d skipped=%d errors=%d, want 3, 1, 1, 1",
+			report.Tests, report.Failures, report.Skipped, report.Errors)
+	}
+	if report.Time != "0.350" {
+		t.Errorf("junitReport() got time %q, want %q", report.Time, "0.350")
+	}
+	a := report.Suites[0]
+	if a.Timestamp != "2026-10-18T12:00:00" {
+		t.Errorf("junitReport() got timestamp %q", a.Timestamp)
+	}
+	if a.Cases[0].Failure != nil || a.Cases[0].Skipped != nil || a.Cases[0].Time != "0.100" {
+		t.Errorf("junitReport() got passing case %+v", a.Cases[0])
+	}
+	if a.Cases[1].Failure == nil || !strings.Contains(a.Cases[1].Failure.Contents, "a_test.go:12: oops") {
+		t.Errorf("junitReport() got failing case %+v", a.Cases[1])
+	}
+	if a.Cases[2].Skipped == nil || a.Cases[2].Skipped.Message != "a_test.go:20: not today" {
+		t.Errorf("junitReport() got skipped case %+v", a.Cases[2])
+	}
+	b := report.Suites[1]
+	if b.Errors != 1 || !strings.Contains(b.SystemOut, "[build failed]") {
+		t.Errorf("junitReport() got build failure suite %+v", b)
+	}
+}
+
+func Test_skipMessage(t *testing.T) {
+	tests := map[string]struct {
+		output []string
+		want   string
+	}{
+		"no output": {
+			output: nil,
+			want:   "Skipped",
+		},
+		"no reason": {
+			output: []string{"=== RUN   TestX\n", "--- SKIP: TestX (0.00s)\n"},
+			want:   "Skipped",
+		},
+		"reason": {
+			output: []string{"=== RUN   TestX\n", "    x_test.go:9: flaky\n", "--- SKIP: TestX (0.00s)\n"},
+			want:   "x_test.go:9: flaky",
+		},
+		"reason after logging": {
+			output: []string{
+				"=== RUN   TestX\n",
+				"    x_test.go:7: setting up\n",
+				"=== PAUSE TestX\n",
+				"=== CONT  TestX\n",
+				"    x_test.go:9: not on this platform\n",
+				"--- SKIP: TestX (0.00s)\n",
+			},
+			want: "x_test.go:9: not on this platform",
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			if got := skipMessage(tt.output); got != tt.want {
+				t.Errorf("skipMessage() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_writeJUnitReport(t *testing.T) {
+	originalBuildFS := BuildFS
+	originalCachedWorkingDir := CachedWorkingDir
+	defer func() {
+		BuildFS = originalBuildFS
+		CachedWorkingDir = originalCachedWorkingDir
+	}()
+	CachedWorkingDir = "work"
+	tests := map[string]struct {
+		fs          afero.Fs
+		wantContent []string
+		want        bool
+	}{
+		"read-only": {
+			fs:   afero.NewReadOnlyFs(afero.NewMemMapFs()),
+			want: false,
+		},
+		"writable": {
+			fs: afero.NewMemMapFs(),
+			wantContent: []string{
+				`<?xml version="1.0" encoding="UTF-8"?>`,
+				`<testsuites tests="3" failures="1" errors="1" skipped="1" time="0.350">`,
+				`<testcase classname="example.com/a" name="TestFail" time="0.200">`,
+				`<failure message="Failed">`,
+				`<skipped message="a_test.go:20: not today">`,
+			},
+			want: true,
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			BuildFS = tt.fs
+			if got := writeJUnitReport("junit.xml", parseTestEvents(sampleTestEvents)); got != tt.want {
+				t.Errorf("writeJUnitReport() = %v, want %v", got, tt.want)
+			}
+			content, _ := afero.ReadFile(BuildFS, "work/junit.xml")
+			for _, want := range tt.wantContent {
+				if !strings.Contains(string(content), want) {
+					t.Errorf("writeJUnitReport() content missing %q: %s", want, content)
+				}
+			}
+		})
+	}
+}
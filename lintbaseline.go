@@ -0,0 +1,228 @@
+package tools_build
+
+import (
+	"cmp"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/goyek/goyek/v3"
+	"github.com/spf13/afero"
+)
+
+// Copyright © 2026 Marc Johnson (marc.johnson27591@gmail.com)
+
+// DefaultLintBaselineFile is the lint baseline file used when no other file is
+// named
+const DefaultLintBaselineFile = "lint-baseline.json"
+
+var (
+	// LintBaselineFlag is a flag that names the lint baseline file
+	LintBaselineFlag = flag.String(
+		"lintbaseline",
+		DefaultLintBaselineFile,
+		"set to the name of the file listing pre-existing lint findings that are not to fail the build")
+	// LintBaselineUpdateFlag is a flag that replaces the lint baseline with the current findings
+	LintBaselineUpdateFlag = flag.Bool(
+		"lintbaselineupdate",
+		false,
+		"set to replace the lint baseline with the current lint findings")
+)
+
+// lintBaselineEntry records how many times a finding, identified by its file,
+// rule and normalized message, occurs in the baseline. A baseline file is a
+// JSON array of entries, such as
+//
+//	[
+//	  {
+//	    "file": "internal/parser.go",
+//	    "rule": "hugeParam",
+//	    "message": "cfg is # bytes; consider passing it by pointer",
+//	    "count": 2
+//	  }
+//	]
+type lintBaselineEntry struct {
+	File    string `json:"file"`
+	Rule    string `json:"rule"`
+	Message string `json:"message"`
+	Count   int    `json:"count"`
+}
+
+// lintBaselineKey identifies a finding without reference to its position, so
+// that baselined findings survive the insertion or deletion of nearby lines
+type lintBaselineKey struct {
+	file    string
+	rule    string
+	message string
+}
+
+var (
+	lintNumbers    = regexp.MustCompile(`\d+`)
+	lintWhitespace = regexp.MustCompile(`\s+`)
+)
+
+// normalizeLintMessage replaces the numbers in a message, which often refer to
+// line numbers or sizes, with '#', and collapses runs of white space
+func normalizeLintMessage(message string) string {
+	return strings.TrimSpace(lintWhitespace.ReplaceAllString(lintNumbers.ReplaceAllString(message, "#"), " "))
+}
+
+func newLintBaselineKey(f Finding) lintBaselineKey {
+	return lintBaselineKey{file: f.File, rule: f.Rule, message: normalizeLintMessage(f.Message)}
+}
+
+// lintBaseline maps baselined findings to the number of times each occurs
+type lintBaseline map[lintBaselineKey]int
+
+// newLintBaseline counts the findings by key
+func newLintBaseline(findings []Finding) lintBaseline {
+	baseline := lintBaseline{}
+	for _, f := range findings {
+		baseline[newLintBaselineKey(f)]++
+	}
+	return baseline
+}
+
+// newFindings returns the findings that are not in the baseline; if a finding
+// occurs more often than the baseline allows, the excess occurrences are new.
+// Also returns the number of baselined findings that no longer occur
+func (lb lintBaseline) newFindings(findings Findings) (fresh Findings, fixed int) {
+	remaining := lintBaseline{}
+	for key, count := range lb {
+		remaining[key] = count
+	}
+	fresh = make(Findings, 0)
+	for _, f := range findings.Sorted() {
+		key := newLintBaselineKey(f)
+		if remaining[key] > 0 {
+			remaining[key]--
+			continue
+		}
+		fresh = append(fresh, f)
+	}
+	for _, count := range remaining {
+		fixed += count
+	}
+	return fresh, fixed
+}
+
+// entries renders the baseline as entries sorted by file, rule and message
+func (lb lintBaseline) entries() []lintBaselineEntry {
+	entries := make([]lintBaselineEntry, 0, len(lb))
+	for key, count := range lb {
+		entries = append(entries, lintBaselineEntry{File: key.file, Rule: key.rule, Message: key.message, Count: count})
+	}
+	slices.SortFunc(entries, func(e1, e2 lintBaselineEntry) int {
+		return cmp.Or(cmp.Compare(e1.File, e2.File), cmp.Compare(e1.Rule, e2.Rule), cmp.Compare(e1.Message, e2.Message))
+	})
+	return entries
+}
+
+// readLintBaseline reads the named baseline file, which is located relative to
+// WorkingDir(); a missing file means an empty baseline. Returns false, after
+// reporting the error, if the file cannot be read or parsed
+func readLintBaseline(baselineFile string) (lintBaseline, bool) {
+	fileName := filepath.Join(WorkingDir(), baselineFile)
+	baseline := lintBaseline{}
+	if exists, _ := afero.Exists(BuildFS, fileName); !exists {
+		return baseline, true
+	}
+	content, err := afero.ReadFile(BuildFS, fileName)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "error %v reading %q\n", err, fileName)
+		return nil, false
+	}
+	var entries []lintBaselineEntry
+	if err = json.Unmarshal(content, &entries); err != nil {
+		fmt.Fprintf(os.Stderr, "error %v parsing %q\n", err, fileName)
+		return nil, false
+	}
+	for _, entry := range entries {
+		key := lintBaselineKey{file: entry.File, rule: entry.Rule, message: normalizeLintMessage(entry.Message)}
+		baseline[key] += max(entry.Count, 1)
+	}
+	return baseline, true
+}
+
+// writeLintBaseline replaces the named baseline file with the findings
+func writeLintBaseline(baselineFile string, findings []Finding) bool {
+	content, _ := json.MarshalIndent(newLintBaseline(findings).entries(), "", "  ")
+	fmt.Printf("saving %d lint findings as the baseline in %q\n", len(findings), baselineFile)
+	return writeWorkingFile(baselineFile, append(content, '\n'))
+}
+
+// UpdateLintBaseline runs the linters selected by the -linters flag (see
+// FlagLinters) and replaces the lint baseline file, named by the
+// -lintbaseline flag, with their findings; returns false on failure
+func UpdateLintBaseline(a *goyek.A) bool {
+	selected, ok := FlagLinters()
+	return ok && updateLintBaseline(a, selected)
+}
+
+func updateLintBaseline(a *goyek.A, selected []Linter) bool {
+	baselineFile := *LintBaselineFlag
+	if isIllegalFileName(baselineFile) {
+		fmt.Fprintf(os.Stderr, "cannot accept %q as a valid lint baseline file name\n", baselineFile)
+		return false
+	}
+	findings, ok := runLinters(a, selected)
+	return ok && writeLintBaseline(baselineFile, findings)
+}
+
+// runLinters runs each linter in turn, reporting its findings (see
+// reportFindings); returns all the findings, and false if any linter cannot be
+// run
+func runLinters(a *goyek.A, selected []Linter) (Findings, bool) {
+	all := make(Findings, 0)
+	for _, linter := range selected {
+		findings, ok := RunLinter(a, linter)
+		if !ok || !reportFindings(linter.Name(), findings) {
+			return nil, false
+		}
+		all = append(all, findings...)
+	}
+	return all, true
+}
+
+// lintAgainstBaseline runs the linters, reports their findings, and returns
+// false if any finding is not in the baseline file named by the -lintbaseline
+// flag; if the -lintbaselineupdate flag is set, the baseline is replaced
+// instead
+func lintAgainstBaseline(a *goyek.A, selected []Linter) bool {
+	if *LintBaselineUpdateFlag {
+		return updateLintBaseline(a, selected)
+	}
+	baselineFile := *LintBaselineFlag
+	if isIllegalFileName(baselineFile) {
+		fmt.Fprintf(os.Stderr, "cannot accept %q as a valid lint baseline file name\n", baselineFile)
+		return false
+	}
+	baseline, ok := readLintBaseline(baselineFile)
+	if !ok {
+		return false
+	}
+	findings, ok := runLinters(a, selected)
+	if !ok {
+		return false
+	}
+	fresh, fixed := baseline.newFindings(findings)
+	if fixed > 0 {
+		fmt.Printf("%d baselined lint findings no longer occur; consider updating the baseline in %q\n", fixed,
+			baselineFile)
+	}
+	if len(fresh) == 0 {
+		return true
+	}
+	if len(baseline) != 0 {
+		fmt.Fprintf(os.Stderr, "%d lint findings are not in the baseline:\n", len(fresh))
+		for _, f := range fresh {
+			fmt.Fprintln(os.Stderr, f.String())
+		}
+	}
+	return false
+}
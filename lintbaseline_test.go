@@ -0,0 +1,230 @@
+package tools_build
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/goyek/goyek/v3"
+	"github.com/goyek/x/cmd"
+	"github.com/spf13/afero"
+)
+
+// Copyright © 2026 Marc Johnson (marc.johnson27591@gmail.com)
+
+func Test_normalizeLintMessage(t *testing.T) {
+	tests := map[string]struct {
+		message string
+		want    string
+	}{
+		"plain":      {message: "duplicate import", want: "duplicate import"},
+		"numbers":    {message: "cfg is 120 bytes; see line 42", want: "cfg is # bytes; see line #"},
+		"whitespace": {message: "  too\tmuch   space ", want: "too much space"},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			if got := normalizeLintMessage(tt.message); got != tt.want {
+				t.Errorf("normalizeLintMessage() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_lintBaseline_newFindings(t *testing.T) {
+	hugeParam := Finding{Tool: "gocritic", File: "a.go", Line: 3, Rule: "hugeParam", Message: "cfg is 120 bytes"}
+	shifted := hugeParam
+	shifted.Line = 30
+	dupImport := Finding{Tool: "gocritic", File: "a.go", Line: 1, Rule: "dupImport", Message: "duplicate import"}
+	otherFile := hugeParam
+	otherFile.File = "b.go"
+	baseline := newLintBaseline([]Finding{hugeParam, dupImport})
+	tests := map[string]struct {
+		findings  Findings
+		wantFresh Findings
+		wantFixed int
+	}{
+		"unchanged": {
+			findings:  Findings{hugeParam, dupImport},
+			wantFresh: Findings{},
+		},
+		"line shift": {
+			findings:  Findings{shifted, dupImport},
+			wantFresh: Findings{},
+		},
+		"fixed": {
+			findings:  Findings{hugeParam},
+			wantFresh: Findings{},
+			wantFixed: 1,
+		},
+		"new file": {
+			findings:  Findings{hugeParam, dupImport, otherFile},
+			wantFresh: Findings{otherFile},
+		},
+		"extra occurrence": {
+			findings:  Findings{shifted, hugeParam, dupImport},
+			wantFresh: Findings{shifted},
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			gotFresh, gotFixed := baseline.newFindings(tt.findings)
+			if !reflect.DeepEqual(gotFresh, tt.wantFresh) {
+				t.Errorf("newFindings() fresh = %v, want %v", gotFresh, tt.wantFresh)
+			}
+			if gotFixed != tt.wantFixed {
+				t.Errorf("newFindings() fixed = %d, want %d", gotFixed, tt.wantFixed)
+			}
+		})
+	}
+}
+
+func Test_readLintBaseline(t *testing.T) {
+	originalBuildFS := BuildFS
+	originalCachedWorkingDir := CachedWorkingDir
+	defer func() {
+		BuildFS = originalBuildFS
+		CachedWorkingDir = originalCachedWorkingDir
+	}()
+	CachedWorkingDir = "work"
+	tests := map[string]struct {
+		content string
+		want    lintBaseline
+		wantOk  bool
+	}{
+		"missing file": {
+			want:   lintBaseline{},
+			wantOk: true,
+		},
+		"unparseable file": {
+			content: "{",
+			wantOk:  false,
+		},
+		"baseline": {
+			content: `[{"file": "a.go", "rule": "hugeParam", "message": "cfg is 80 bytes", "count": 2},
+				{"file": "b.go", "rule": "dupImport", "message": "duplicate import"}]`,
+			want: lintBaseline{
+				{file: "a.go", rule: "hugeParam", message: "cfg is # bytes"}:   2,
+				{file: "b.go", rule: "dupImport", message: "duplicate import"}: 1,
+			},
+			wantOk: true,
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			BuildFS = afero.NewMemMapFs()
+			if tt.content != "" {
+				_ = afero.WriteFile(BuildFS, "work/baseline.json", []byte(tt.content), fileMode)
+			}
+			got, gotOk := readLintBaseline("baseline.json")
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("readLintBaseline() = %v, want %v", got, tt.want)
+			}
+			if gotOk != tt.wantOk {
+				t.Errorf("readLintBaseline() ok = %v, want %v", gotOk, tt.wantOk)
+			}
+		})
+	}
+}
+
+func TestLint_baseline(t *testing.T) {
+	originalBuildFS := BuildFS
+	originalCachedWorkingDir := CachedWorkingDir
+	originalExecFn := ExecFn
+	originalCmdCapture := cmdCapture
+	originalLintBaselineFlag := LintBaselineFlag
+	originalLintBaselineUpdateFlag := LintBaselineUpdateFlag
+	defer func() {
+		BuildFS = originalBuildFS
+		CachedWorkingDir = originalCachedWorkingDir
+		ExecFn = originalExecFn
+		cmdCapture = originalCmdCapture
+		LintBaselineFlag = originalLintBaselineFlag
+		LintBaselineUpdateFlag = originalLintBaselineUpdateFlag
+	}()
+	CachedWorkingDir = "work"
+	ExecFn = func(_ *goyek.A, _ string, _ ...cmd.Option) bool {
+		return true
+	}
+	lintOutput := "a.go:3:1: hugeParam: cfg is 80 bytes\na.go:9:1: dupImport: duplicate import\n"
+	cmdCapture = func(_ *goyek.A, _ directedCommand) (bool, string, string) {
+		return false, lintOutput, ""
+	}
+	baseline := "[\n" +
+		"  {\n" +
+		"    \"file\": \"a.go\",\n" +
+		"    \"rule\": \"dupImport\",\n" +
+		"    \"message\": \"duplicate import\",\n" +
+		"    \"count\": 1\n" +
+		"  },\n" +
+		"  {\n" +
+		"    \"file\": \"a.go\",\n" +
+		"    \"rule\": \"hugeParam\",\n" +
+		"    \"message\": \"cfg is # bytes\",\n" +
+		"    \"count\": 1\n" +
+		"  }\n" +
+		"]\n"
+	tests := map[string]struct {
+		baselineFile string
+		existing     string
+		update       bool
+		want         bool
+		wantBaseline string
+	}{
+		"bad baseline file name": {
+			baselineFile: "../baseline.json",
+			want:         false,
+		},
+		"bad baseline file name on update": {
+			baselineFile: "../baseline.json",
+			update:       true,
+			want:         false,
+		},
+		"unparseable baseline": {
+			baselineFile: "baseline.json",
+			existing:     "[",
+			want:         false,
+		},
+		"no baseline": {
+			baselineFile: "baseline.json",
+			want:         false,
+		},
+		"partial baseline": {
+			baselineFile: "baseline.json",
+			existing:     `[{"file": "a.go", "rule": "dupImport", "message": "duplicate import", "count": 1}]`,
+			want:         false,
+		},
+		"complete baseline": {
+			baselineFile: "baseline.json",
+			existing:     baseline,
+			want:         true,
+		},
+		"update": {
+			baselineFile: "baseline.json",
+			existing:     `[{"file": "a.go", "rule": "dupImport", "message": "duplicate import", "count": 1}]`,
+			update:       true,
+			want:         true,
+			wantBaseline: baseline,
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			BuildFS = afero.NewMemMapFs()
+			_ = BuildFS.MkdirAll("work", dirMode)
+			if tt.existing != "" {
+				_ = afero.WriteFile(BuildFS, "work/"+tt.baselineFile, []byte(tt.existing), fileMode)
+			}
+			baselineFile := tt.baselineFile
+			LintBaselineFlag = &baselineFile
+			update := tt.update
+			LintBaselineUpdateFlag = &update
+			if got := Lint(nil); got != tt.want {
+				t.Errorf("Lint() = %v, want %v", got, tt.want)
+			}
+			if tt.wantBaseline != "" {
+				content, _ := afero.ReadFile(BuildFS, "work/"+tt.baselineFile)
+				if string(content) != tt.wantBaseline {
+					t.Errorf("Lint() baseline = %q, want %q", content, tt.wantBaseline)
+				}
+			}
+		})
+	}
+}
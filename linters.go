@@ -0,0 +1,282 @@
+package tools_build
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"maps"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/goyek/goyek/v3"
+)
+
+// Copyright © 2026 Marc Johnson (marc.johnson27591@gmail.com)
+
+// LintersFlag is a flag that selects, by name, the linters that Lint runs
+var LintersFlag = flag.String(
+	"linters",
+	"gocritic",
+	"set to a comma-delimited set of linters (gocritic, staticcheck, vet, revive, or registered linters) for lint to run")
+
+// Linter is a lint tool that Lint can run; see RegisterLinter
+type Linter interface {
+	// Name identifies the linter; it is also the tool name of the linter's
+	// findings
+	Name() string
+	// Install makes sure that the linter is ready to run, typically by
+	// installing its latest version; returns false on failure
+	Install(a *goyek.A) bool
+	// Command returns the command line that runs the linter over every package
+	// in WorkingDir()
+	Command() string
+	// Parse extracts the findings from the linter's output; state is true if
+	// the command succeeded. Returns false if the output shows that the linter
+	// could not run
+	Parse(state bool, stdout, stderr string) (Findings, bool)
+}
+
+// linters holds the functions that create the registered linters, keyed by
+// name
+var linters = map[string]func() Linter{
+	"gocritic":    func() Linter { return GocriticLinter{Options: FlagLintOptions()} },
+	"staticcheck": func() Linter { return StaticcheckLinter{} },
+	"vet":         func() Linter { return VetLinter{Options: FlagVetOptions()} },
+	"revive":      func() Linter { return ReviveLinter{} },
+}
+
+// RegisterLinter makes a linter available, by name, to the -linters flag;
+// newLinter is called each time that the linter is selected, so that it can
+// read command line flags. Registering a linter under the name of a built-in
+// linter replaces the built-in linter
+func RegisterLinter(name string, newLinter func() Linter) {
+	linters[name] = newLinter
+}
+
+// FlagLinters returns the linters selected by the -linters flag; returns false,
+// after reporting the error, if any selected linter is not registered
+func FlagLinters() ([]Linter, bool) {
+	selected := make([]Linter, 0)
+	valid := true
+	for _, name := range splitList(*LintersFlag) {
+		newLinter, found := linters[name]
+		if !found {
+			fmt.Fprintf(os.Stderr, "cannot accept %q as a linter; use one of %s\n", name,
+				strings.Join(slices.Sorted(maps.Keys(linters)), ", "))
+			valid = false
+			continue
+		}
+		selected = append(selected, newLinter())
+	}
+	return selected, valid
+}
+
+// RunLinter installs and runs the linter, returning its findings; returns false
+// if the linter cannot be run
+func RunLinter(a *goyek.A, linter Linter) (Findings, bool) {
+	if !linter.Install(a) {
+		return nil, false
+	}
+	printIt(fmt.Sprintf("running %s", linter.Name()))
+	return runLinterIn(a, linter, WorkingDir())
+}
+
+// runLinterIn runs the installed linter in the specified directory, returning
+// its findings; returns false if the linter cannot be run
+func runLinterIn(a *goyek.A, linter Linter, dir string) (Findings, bool) {
+	state, stdout, stderr := cmdCapture(a, directedCommand{command: linter.Command(), dir: dir})
+	findings, ok := linter.Parse(state, stdout, stderr)
+	if !ok {
+		printIt(strings.TrimSpace(stdout + "\n" + stderr))
+		return nil, false
+	}
+	return findings, true
+}
+
+// GocriticLinter runs gocritic, as directed by its options
+type GocriticLinter struct {
+	Options LintOptions
+}
+
+// Name returns "gocritic"
+func (GocriticLinter) Name() string {
+	return "gocritic"
+}
+
+// Install validates the options, and installs the latest version of gocritic
+func (gl GocriticLinter) Install(a *goyek.A) bool {
+	return gl.Options.validate() && Install(a, "github.com/go-critic/go-critic/cmd/gocritic")
+}
+
+// Command returns the gocritic command line described by the options
+func (gl GocriticLinter) Command() string {
+	return gl.Options.command()
+}
+
+// Parse extracts the findings from gocritic's output, omitting those in the
+// paths excluded by the options; gocritic fails when it finds problems, so
+// failing without any means that it could not run
+func (gl GocriticLinter) Parse(state bool, stdout, stderr string) (Findings, bool) {
+	findings := Findings(parseGocriticOutput(stdout + "\n" + stderr))
+	if !state && len(findings) == 0 {
+		return nil, false
+	}
+	return findings.ExcludingPaths(gl.Options.Exclude...), true
+}
+
+// StaticcheckLinter runs staticcheck
+type StaticcheckLinter struct {
+	// Checks, if not empty, selects the checks to run, such as "all" or
+	// "-ST1000"; otherwise, staticcheck's defaults, or the project's
+	// staticcheck.conf, apply
+	Checks []string
+}
+
+// Name returns "staticcheck"
+func (StaticcheckLinter) Name() string {
+	return "staticcheck"
+}
+
+// Install installs the latest version of staticcheck
+func (StaticcheckLinter) Install(a *goyek.A) bool {
+	return Install(a, "honnef.co/go/tools/cmd/staticcheck")
+}
+
+// Command returns the staticcheck command line, with JSON output
+func (sl StaticcheckLinter) Command() string {
+	cmdParts := []string{"staticcheck", "-f", "json"}
+	if len(sl.Checks) != 0 {
+		cmdParts = append(cmdParts, "-checks="+quoteArg(strings.Join(sl.Checks, ",")))
+	}
+	cmdParts = append(cmdParts, "./...")
+	return strings.Join(cmdParts, " ")
+}
+
+// staticcheckProblem is a problem in the JSON written by 'staticcheck -f json'
+type staticcheckProblem struct {
+	Code     string `json:"code"`
+	Severity string `json:"severity"`
+	Location struct {
+		File   string `json:"file"`
+		Line   int    `json:"line"`
+		Column int    `json:"column"`
+	} `json:"location"`
+	Message string `json:"message"`
+}
+
+// Parse decodes the stream of problems written by staticcheck; staticcheck
+// fails when it finds problems, so failing without any means that it could not
+// run
+func (StaticcheckLinter) Parse(state bool, stdout, _ string) (Findings, bool) {
+	decoder := json.NewDecoder(strings.NewReader(stdout))
+	findings := make(Findings, 0)
+	for {
+		var problem staticcheckProblem
+		err := decoder.Decode(&problem)
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "error %v parsing staticcheck output\n", err)
+			return nil, false
+		}
+		if problem.Code == "compile" {
+			// the code cannot be type checked, so staticcheck could not run
+			return nil, false
+		}
+		severity := SeverityWarning
+		if problem.Severity == "error" {
+			severity = SeverityError
+		}
+		findings = append(findings, Finding{
+			Tool:     "staticcheck",
+			File:     relativeFindingPath(problem.Location.File),
+			Line:     problem.Location.Line,
+			Column:   problem.Location.Column,
+			Rule:     problem.Code,
+			Message:  problem.Message,
+			Severity: severity,
+		})
+	}
+	if !state && len(findings) == 0 {
+		return nil, false
+	}
+	return findings, true
+}
+
+// ReviveLinter runs revive
+type ReviveLinter struct {
+	// Config, if not empty, names revive's TOML configuration file, relative
+	// to WorkingDir(); otherwise, revive's defaults apply
+	Config string
+}
+
+// Name returns "revive"
+func (ReviveLinter) Name() string {
+	return "revive"
+}
+
+// Install installs the latest version of revive
+func (ReviveLinter) Install(a *goyek.A) bool {
+	return Install(a, "github.com/mgechev/revive")
+}
+
+// Command returns the revive command line, with JSON output
+func (rl ReviveLinter) Command() string {
+	cmdParts := []string{"revive", "-formatter", "json"}
+	if rl.Config != "" {
+		cmdParts = append(cmdParts, "-config", quoteArg(rl.Config))
+	}
+	cmdParts = append(cmdParts, "./...")
+	return strings.Join(cmdParts, " ")
+}
+
+// reviveFailure is a failure in the JSON written by 'revive -formatter json'
+type reviveFailure struct {
+	Failure  string
+	RuleName string
+	Severity string
+	Position struct {
+		Start struct {
+			Filename string
+			Line     int
+			Column   int
+		}
+	}
+}
+
+// Parse decodes the array of failures written by revive; revive fails, if
+// configured to do so, when it finds problems, so failing without any means
+// that it could not run
+func (ReviveLinter) Parse(state bool, stdout, _ string) (Findings, bool) {
+	var failures []reviveFailure
+	if strings.TrimSpace(stdout) != "" {
+		if err := json.Unmarshal([]byte(stdout), &failures); err != nil {
+			fmt.Fprintf(os.Stderr, "error %v parsing revive output\n", err)
+			return nil, false
+		}
+	}
+	if !state && len(failures) == 0 {
+		return nil, false
+	}
+	findings := make(Findings, 0, len(failures))
+	for _, failure := range failures {
+		severity := SeverityWarning
+		if failure.Severity == "error" {
+			severity = SeverityError
+		}
+		findings = append(findings, Finding{
+			Tool:     "revive",
+			File:     relativeFindingPath(failure.Position.Start.Filename),
+			Line:     failure.Position.Start.Line,
+			Column:   failure.Position.Start.Column,
+			Rule:     failure.RuleName,
+			Message:  failure.Failure,
+			Severity: severity,
+		})
+	}
+	return findings, true
+}
@@ -0,0 +1,291 @@
+package tools_build
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/goyek/goyek/v3"
+	"github.com/goyek/x/cmd"
+	"github.com/spf13/afero"
+)
+
+// Copyright © 2026 Marc Johnson (marc.johnson27591@gmail.com)
+
+// testLinter is a Linter whose behavior is set by its fields
+type testLinter struct {
+	name       string
+	installs   bool
+	parseFails bool
+	findings   Findings
+}
+
+func (tl testLinter) Name() string            { return tl.name }
+func (tl testLinter) Install(_ *goyek.A) bool { return tl.installs }
+func (tl testLinter) Command() string         { return tl.name + " ./..." }
+func (tl testLinter) Parse(bool, string, string) (Findings, bool) {
+	if tl.parseFails {
+		return nil, false
+	}
+	return tl.findings, true
+}
+
+func TestFlagLinters(t *testing.T) {
+	originalLintersFlag := LintersFlag
+	originalLinters := linters
+	defer func() {
+		LintersFlag = originalLintersFlag
+		linters = originalLinters
+	}()
+	linters = map[string]func() Linter{}
+	for name, newLinter := range originalLinters {
+		linters[name] = newLinter
+	}
+	RegisterLinter("custom", func() Linter { return testLinter{name: "custom"} })
+	tests := map[string]struct {
+		flag      string
+		wantNames []string
+		wantOk    bool
+	}{
+		"default":    {flag: "gocritic", wantNames: []string{"gocritic"}, wantOk: true},
+		"built-ins":  {flag: "staticcheck, vet,revive", wantNames: []string{"staticcheck", "vet", "revive"}, wantOk: true},
+		"registered": {flag: "custom", wantNames: []string{"custom"}, wantOk: true},
+		"unknown":    {flag: "golint,vet", wantNames: []string{"vet"}, wantOk: false},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			LintersFlag = &tt.flag
+			got, gotOk := FlagLinters()
+			gotNames := make([]string, 0, len(got))
+			for _, linter := range got {
+				gotNames = append(gotNames, linter.Name())
+			}
+			if !reflect.DeepEqual(gotNames, tt.wantNames) {
+				t.Errorf("FlagLinters() = %v, want %v", gotNames, tt.wantNames)
+			}
+			if gotOk != tt.wantOk {
+				t.Errorf("FlagLinters() ok = %v, want %v", gotOk, tt.wantOk)
+			}
+		})
+	}
+}
+
+func TestRunLinter(t *testing.T) {
+	originalCachedWorkingDir := CachedWorkingDir
+	originalCmdCapture := cmdCapture
+	defer func() {
+		CachedWorkingDir = originalCachedWorkingDir
+		cmdCapture = originalCmdCapture
+	}()
+	CachedWorkingDir = "work"
+	finding := Finding{Tool: "custom", File: "a.go", Line: 1, Rule: "r", Message: "m", Severity: SeverityNote}
+	tests := map[string]struct {
+		linter      testLinter
+		wantCommand string
+		want        Findings
+		wantOk      bool
+	}{
+		"install fails": {
+			linter: testLinter{name: "custom"},
+			wantOk: false,
+		},
+		"parse fails": {
+			linter:      testLinter{name: "custom", installs: true, parseFails: true},
+			wantCommand: "custom ./...",
+			wantOk:      false,
+		},
+		"success": {
+			linter:      testLinter{name: "custom", installs: true, findings: Findings{finding}},
+			wantCommand: "custom ./...",
+			want:        Findings{finding},
+			wantOk:      true,
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			gotCommand := ""
+			cmdCapture = func(_ *goyek.A, dC directedCommand) (bool, string, string) {
+				gotCommand = dC.command
+				return true, "", ""
+			}
+			got, gotOk := RunLinter(nil, tt.linter)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("RunLinter() = %v, want %v", got, tt.want)
+			}
+			if gotOk != tt.wantOk {
+				t.Errorf("RunLinter() ok = %v, want %v", gotOk, tt.wantOk)
+			}
+			if gotCommand != tt.wantCommand {
+				t.Errorf("RunLinter() command = %q, want %q", gotCommand, tt.wantCommand)
+			}
+		})
+	}
+}
+
+func TestStaticcheckLinter(t *testing.T) {
+	if got, want := (StaticcheckLinter{}).Command(), "staticcheck -f json ./..."; got != want {
+		t.Errorf("Command() = %q, want %q", got, want)
+	}
+	if got, want := (StaticcheckLinter{Checks: []string{"all", "-ST1000"}}).Command(),
+		"staticcheck -f json -checks=all,-ST1000 ./..."; got != want {
+		t.Errorf("Command() = %q, want %q", got, want)
+	}
+	tests := map[string]struct {
+		state  bool
+		stdout string
+		want   Findings
+		wantOk bool
+	}{
+		"clean": {
+			state:  true,
+			want:   Findings{},
+			wantOk: true,
+		},
+		"cannot run": {
+			state:  false,
+			wantOk: false,
+		},
+		"unparseable": {
+			state:  false,
+			stdout: "{",
+			wantOk: false,
+		},
+		"compile error": {
+			state:  false,
+			stdout: `{"code": "compile", "severity": "error", "location": {"file": "a.go", "line": 1, "column": 1}, "message": "undefined: x"}`,
+			wantOk: false,
+		},
+		"problems": {
+			state: false,
+			stdout: `{"code": "SA4006", "severity": "error", "location": {"file": "a.go", "line": 3, "column": 2}, "message": "value never used"}
+{"code": "ST1003", "severity": "warning", "location": {"file": "b.go", "line": 7, "column": 6}, "message": "bad name"}
+`,
+			want: Findings{
+				{Tool: "staticcheck", File: "a.go", Line: 3, Column: 2, Rule: "SA4006", Message: "value never used", Severity: SeverityError},
+				{Tool: "staticcheck", File: "b.go", Line: 7, Column: 6, Rule: "ST1003", Message: "bad name", Severity: SeverityWarning},
+			},
+			wantOk: true,
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, gotOk := StaticcheckLinter{}.Parse(tt.state, tt.stdout, "")
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Parse() = %v, want %v", got, tt.want)
+			}
+			if gotOk != tt.wantOk {
+				t.Errorf("Parse() ok = %v, want %v", gotOk, tt.wantOk)
+			}
+		})
+	}
+}
+
+func TestReviveLinter(t *testing.T) {
+	if got, want := (ReviveLinter{}).Command(), "revive -formatter json ./..."; got != want {
+		t.Errorf("Command() = %q, want %q", got, want)
+	}
+	if got, want := (ReviveLinter{Config: "my revive.toml"}).Command(),
+		"revive -formatter json -config 'my revive.toml' ./..."; got != want {
+		t.Errorf("Command() = %q, want %q", got, want)
+	}
+	tests := map[string]struct {
+		state  bool
+		stdout string
+		want   Findings
+		wantOk bool
+	}{
+		"clean": {
+			state:  true,
+			stdout: "[]",
+			want:   Findings{},
+			wantOk: true,
+		},
+		"cannot run": {
+			state:  false,
+			wantOk: false,
+		},
+		"unparseable": {
+			state:  true,
+			stdout: "[",
+			wantOk: false,
+		},
+		"failures": {
+			state: true,
+			stdout: `[{"Severity": "warning", "Failure": "exported function F should have comment", "RuleName": "exported",
+				"Position": {"Start": {"Filename": "a.go", "Line": 5, "Column": 1}}},
+				{"Severity": "error", "Failure": "unreachable code", "RuleName": "unreachable-code",
+				"Position": {"Start": {"Filename": "b.go", "Line": 8, "Column": 2}}}]`,
+			want: Findings{
+				{Tool: "revive", File: "a.go", Line: 5, Column: 1, Rule: "exported", Message: "exported function F should have comment", Severity: SeverityWarning},
+				{Tool: "revive", File: "b.go", Line: 8, Column: 2, Rule: "unreachable-code", Message: "unreachable code", Severity: SeverityError},
+			},
+			wantOk: true,
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, gotOk := ReviveLinter{}.Parse(tt.state, tt.stdout, "")
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Parse() = %v, want %v", got, tt.want)
+			}
+			if gotOk != tt.wantOk {
+				t.Errorf("Parse() ok = %v, want %v", gotOk, tt.wantOk)
+			}
+		})
+	}
+}
+
+func TestLintWith(t *testing.T) {
+	originalBuildFS := BuildFS
+	originalCachedWorkingDir := CachedWorkingDir
+	originalCmdCapture := cmdCapture
+	originalExecFn := ExecFn
+	defer func() {
+		BuildFS = originalBuildFS
+		CachedWorkingDir = originalCachedWorkingDir
+		cmdCapture = originalCmdCapture
+		ExecFn = originalExecFn
+		delete(reportedFindings, "first")
+		delete(reportedFindings, "second")
+	}()
+	BuildFS = afero.NewMemMapFs()
+	CachedWorkingDir = "work"
+	cmdCapture = func(_ *goyek.A, _ directedCommand) (bool, string, string) {
+		return true, "", ""
+	}
+	ExecFn = func(_ *goyek.A, _ string, _ ...cmd.Option) bool {
+		return true
+	}
+	finding := Finding{Tool: "second", File: "a.go", Line: 1, Rule: "r", Message: "m", Severity: SeverityNote}
+	tests := map[string]struct {
+		linters   []Linter
+		want      bool
+		wantFirst int
+	}{
+		"clean": {
+			linters: []Linter{testLinter{name: "first", installs: true}, testLinter{name: "second", installs: true}},
+			want:    true,
+		},
+		"second cannot run": {
+			linters: []Linter{testLinter{name: "first", installs: true}, testLinter{name: "second"}},
+			want:    false,
+		},
+		"findings": {
+			linters: []Linter{
+				testLinter{name: "first", installs: true, findings: Findings{finding, finding}},
+				testLinter{name: "second", installs: true, findings: Findings{finding}},
+			},
+			want:      false,
+			wantFirst: 1,
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			if got := LintWith(nil, tt.linters...); got != tt.want {
+				t.Errorf("LintWith() = %v, want %v", got, tt.want)
+			}
+			if got := len(FindingsOf("first")); got != tt.wantFirst {
+				t.Errorf("LintWith() first linter findings = %d, want %d", got, tt.wantFirst)
+			}
+		})
+	}
+}
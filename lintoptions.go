@@ -0,0 +1,122 @@
+package tools_build
+
+import (
+	"flag"
+	"fmt"
+	"maps"
+	"os"
+	"slices"
+	"strings"
+)
+
+// Copyright © 2026 Marc Johnson (marc.johnson27591@gmail.com)
+
+var (
+	// LintDisableFlag is a flag that disables a comma-delimited set of gocritic checkers
+	LintDisableFlag = flag.String(
+		"lintdisable",
+		"",
+		"set to a comma-delimited set of gocritic checkers to disable")
+	// LintEnableFlag is a flag that enables a comma-delimited set of gocritic checkers
+	LintEnableFlag = flag.String(
+		"lintenable",
+		"",
+		"set to a comma-delimited set of gocritic checkers to enable (default all checkers)")
+	// LintExcludeFlag is a flag that excludes a comma-delimited set of path patterns from lint findings
+	LintExcludeFlag = flag.String(
+		"lintexclude",
+		"",
+		"set to a comma-delimited set of path patterns, such as internal/..., whose lint findings are ignored")
+	// LintParamsFlag is a flag that sets a comma-delimited set of gocritic checker parameters
+	LintParamsFlag = flag.String(
+		"lintparams",
+		"",
+		"set to a comma-delimited set of checker.param=value gocritic checker parameters, such as hugeParam.sizeThreshold=512")
+	// LintTagsFlag is a flag that enables the gocritic checkers with any of a comma-delimited set of tags
+	LintTagsFlag = flag.String(
+		"linttags",
+		"",
+		"set to a comma-delimited set of gocritic checker tags (diagnostic, style, performance, experimental, opinionated) to enable")
+)
+
+// lintTags are the gocritic checker tags
+var lintTags = []string{"diagnostic", "experimental", "opinionated", "performance", "style"}
+
+// LintOptions controls which gocritic checkers Lint runs, and how; the zero
+// value enables every checker (-enableAll) and reports every finding
+type LintOptions struct {
+	// Enable lists the checkers to enable; if Enable and Tags are both empty,
+	// every checker is enabled
+	Enable []string
+	// Disable lists the checkers to disable
+	Disable []string
+	// Tags enables the checkers with any of the tags: "diagnostic", "style",
+	// "performance", "experimental" or "opinionated"
+	Tags []string
+	// Params sets checker parameters, keyed by checker.param, such as
+	// "hugeParam.sizeThreshold"
+	Params map[string]string
+	// Exclude lists path patterns, either file paths or directory paths
+	// followed by "/...", whose findings are ignored
+	Exclude []string
+}
+
+// FlagLintOptions returns the LintOptions specified by the command line flags
+func FlagLintOptions() LintOptions {
+	options := LintOptions{
+		Enable:  splitList(*LintEnableFlag),
+		Disable: splitList(*LintDisableFlag),
+		Tags:    splitList(*LintTagsFlag),
+		Exclude: splitList(*LintExcludeFlag),
+	}
+	for _, param := range splitList(*LintParamsFlag) {
+		if options.Params == nil {
+			options.Params = map[string]string{}
+		}
+		name, value, _ := strings.Cut(param, "=")
+		options.Params[name] = value
+	}
+	return options
+}
+
+// validate reports unknown tags and malformed checker parameters
+func (lo LintOptions) validate() bool {
+	valid := true
+	for _, tag := range lo.Tags {
+		if !slices.Contains(lintTags, tag) {
+			fmt.Fprintf(os.Stderr, "cannot accept %q as a gocritic checker tag; use one of %s\n", tag,
+				strings.Join(lintTags, ", "))
+			valid = false
+		}
+	}
+	for _, name := range slices.Sorted(maps.Keys(lo.Params)) {
+		checker, param, found := strings.Cut(strings.TrimPrefix(name, "@"), ".")
+		if !found || checker == "" || param == "" || lo.Params[name] == "" {
+			fmt.Fprintf(os.Stderr, "cannot accept %q as a gocritic checker parameter\n", name+"="+lo.Params[name])
+			valid = false
+		}
+	}
+	return valid
+}
+
+// command assembles the gocritic command line described by the options
+func (lo LintOptions) command() string {
+	cmdParts := []string{"gocritic", "check"}
+	if len(lo.Enable) == 0 && len(lo.Tags) == 0 {
+		cmdParts = append(cmdParts, "-enableAll")
+	} else {
+		enabled := slices.Clone(lo.Enable)
+		for _, tag := range lo.Tags {
+			enabled = append(enabled, "#"+tag)
+		}
+		cmdParts = append(cmdParts, "-enable="+quoteArg(strings.Join(enabled, ",")))
+	}
+	if len(lo.Disable) != 0 {
+		cmdParts = append(cmdParts, "-disable="+quoteArg(strings.Join(lo.Disable, ",")))
+	}
+	for _, name := range slices.Sorted(maps.Keys(lo.Params)) {
+		cmdParts = append(cmdParts, quoteArg("-@"+strings.TrimPrefix(name, "@")+"="+lo.Params[name]))
+	}
+	cmdParts = append(cmdParts, "./...")
+	return strings.Join(cmdParts, " ")
+}
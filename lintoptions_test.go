@@ -0,0 +1,173 @@
+package tools_build
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/goyek/goyek/v3"
+	"github.com/goyek/x/cmd"
+)
+
+// Copyright © 2026 Marc Johnson (marc.johnson27591@gmail.com)
+
+func TestFlagLintOptions(t *testing.T) {
+	originalLintDisableFlag := LintDisableFlag
+	originalLintEnableFlag := LintEnableFlag
+	originalLintExcludeFlag := LintExcludeFlag
+	originalLintParamsFlag := LintParamsFlag
+	originalLintTagsFlag := LintTagsFlag
+	defer func() {
+		LintDisableFlag = originalLintDisableFlag
+		LintEnableFlag = originalLintEnableFlag
+		LintExcludeFlag = originalLintExcludeFlag
+		LintParamsFlag = originalLintParamsFlag
+		LintTagsFlag = originalLintTagsFlag
+	}()
+	tests := map[string]struct {
+		disable string
+		enable  string
+		exclude string
+		params  string
+		tags    string
+		want    LintOptions
+	}{
+		"defaults": {
+			want: LintOptions{},
+		},
+		"everything": {
+			disable: "whyNoLint, commentedOutCode",
+			enable:  "hugeParam",
+			exclude: "internal/...,gen.go",
+			params:  "hugeParam.sizeThreshold=512, rangeValCopy.sizeThreshold=128",
+			tags:    "diagnostic,performance",
+			want: LintOptions{
+				Enable:  []string{"hugeParam"},
+				Disable: []string{"whyNoLint", "commentedOutCode"},
+				Tags:    []string{"diagnostic", "performance"},
+				Params:  map[string]string{"hugeParam.sizeThreshold": "512", "rangeValCopy.sizeThreshold": "128"},
+				Exclude: []string{"internal/...", "gen.go"},
+			},
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			LintDisableFlag = &tt.disable
+			LintEnableFlag = &tt.enable
+			LintExcludeFlag = &tt.exclude
+			LintParamsFlag = &tt.params
+			LintTagsFlag = &tt.tags
+			if got := FlagLintOptions(); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("FlagLintOptions() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLintOptions_validate(t *testing.T) {
+	tests := map[string]struct {
+		options LintOptions
+		want    bool
+	}{
+		"defaults":      {options: LintOptions{}, want: true},
+		"known tags":    {options: LintOptions{Tags: []string{"diagnostic", "style", "performance"}}, want: true},
+		"unknown tag":   {options: LintOptions{Tags: []string{"security"}}, want: false},
+		"good params":   {options: LintOptions{Params: map[string]string{"@hugeParam.sizeThreshold": "512"}}, want: true},
+		"no parameter":  {options: LintOptions{Params: map[string]string{"hugeParam": "512"}}, want: false},
+		"no checker":    {options: LintOptions{Params: map[string]string{".sizeThreshold": "512"}}, want: false},
+		"missing value": {options: LintOptions{Params: map[string]string{"hugeParam.sizeThreshold": ""}}, want: false},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			if got := tt.options.validate(); got != tt.want {
+				t.Errorf("validate() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLintOptions_command(t *testing.T) {
+	tests := map[string]struct {
+		options LintOptions
+		want    string
+	}{
+		"defaults": {
+			options: LintOptions{},
+			want:    "gocritic check -enableAll ./...",
+		},
+		"disabled checkers": {
+			options: LintOptions{Disable: []string{"whyNoLint", "commentedOutCode"}},
+			want:    "gocritic check -enableAll -disable=whyNoLint,commentedOutCode ./...",
+		},
+		"enabled checkers and tags": {
+			options: LintOptions{Enable: []string{"hugeParam"}, Tags: []string{"diagnostic", "performance"}},
+			want:    "gocritic check -enable='hugeParam,#diagnostic,#performance' ./...",
+		},
+		"parameters": {
+			options: LintOptions{
+				Tags:   []string{"performance"},
+				Params: map[string]string{"rangeValCopy.sizeThreshold": "128", "@hugeParam.sizeThreshold": "512"},
+			},
+			want: "gocritic check -enable='#performance' -@hugeParam.sizeThreshold=512 -@rangeValCopy.sizeThreshold=128 ./...",
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			if got := tt.options.command(); got != tt.want {
+				t.Errorf("command() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLintFindingsWithOptions(t *testing.T) {
+	originalCachedWorkingDir := CachedWorkingDir
+	originalExecFn := ExecFn
+	originalCmdCapture := cmdCapture
+	defer func() {
+		CachedWorkingDir = originalCachedWorkingDir
+		ExecFn = originalExecFn
+		cmdCapture = originalCmdCapture
+	}()
+	CachedWorkingDir = "work"
+	ExecFn = func(_ *goyek.A, _ string, _ ...cmd.Option) bool {
+		return true
+	}
+	tests := map[string]struct {
+		options     LintOptions
+		wantCommand string
+		want        Findings
+		wantOk      bool
+	}{
+		"invalid options": {
+			options: LintOptions{Tags: []string{"security"}},
+			wantOk:  false,
+		},
+		"exclusions": {
+			options:     LintOptions{Tags: []string{"style"}, Exclude: []string{"gen/..."}},
+			wantCommand: "gocritic check -enable='#style' ./...",
+			want: Findings{
+				{Tool: "gocritic", File: "a.go", Line: 1, Column: 1, Rule: "dupImport", Message: "duplicate import", Severity: SeverityWarning},
+			},
+			wantOk: true,
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			gotCommand := ""
+			cmdCapture = func(_ *goyek.A, dC directedCommand) (bool, string, string) {
+				gotCommand = dC.command
+				return false, "a.go:1:1: dupImport: duplicate import\ngen/b.go:2:1: dupImport: duplicate import\n", ""
+			}
+			got, gotOk := LintFindingsWithOptions(nil, tt.options)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("LintFindingsWithOptions() = %v, want %v", got, tt.want)
+			}
+			if gotOk != tt.wantOk {
+				t.Errorf("LintFindingsWithOptions() ok = %v, want %v", gotOk, tt.wantOk)
+			}
+			if gotCommand != tt.wantCommand {
+				t.Errorf("LintFindingsWithOptions() command = %q, want %q", gotCommand, tt.wantCommand)
+			}
+		})
+	}
+}
@@ -0,0 +1,85 @@
+package tools_build
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/afero"
+	"golang.org/x/mod/modfile"
+)
+
+// Copyright © 2026 Marc Johnson (marc.johnson27591@gmail.com)
+
+// goModule describes a module found in the working directory tree
+type goModule struct {
+	// dir is the module's directory, relative to WorkingDir(); "" is the
+	// working directory itself
+	dir string
+	// file is the parsed go.mod file
+	file *modfile.File
+}
+
+// path returns the module path declared in the go.mod file
+func (gm goModule) path() string {
+	if gm.file.Module == nil {
+		return ""
+	}
+	return gm.file.Module.Mod.Path
+}
+
+// name returns the module's path, or, if it has none, its directory
+func (gm goModule) name() string {
+	if modulePath := gm.path(); modulePath != "" {
+		return modulePath
+	}
+	return canonicalPath(gm.dir)
+}
+
+// findModules reads and parses every go.mod file found in the working
+// directory tree; returns false, after reporting the error, on failure
+func findModules() ([]goModule, bool) {
+	dirs, err := RelevantDirs(matchModuleFile)
+	if err != nil {
+		return nil, false
+	}
+	modules := make([]goModule, 0, len(dirs))
+	for _, dir := range dirs {
+		fileName := filepath.Join(WorkingDir(), dir, "go.mod")
+		content, readErr := afero.ReadFile(BuildFS, fileName)
+		if readErr != nil {
+			fmt.Fprintf(os.Stderr, "error %v reading %q\n", readErr, fileName)
+			return nil, false
+		}
+		file, parseErr := modfile.Parse(fileName, content, nil)
+		if parseErr != nil {
+			fmt.Fprintf(os.Stderr, "error %v parsing %q\n", parseErr, fileName)
+			return nil, false
+		}
+		modules = append(modules, goModule{dir: dir, file: file})
+	}
+	return modules, true
+}
+
+// repoRelativePath maps a file named by its import path, as found in coverage
+// profiles, to its path relative to WorkingDir(), using the module whose
+// module path is the longest prefix of the file's import path. If no module
+// matches, the import path is returned unchanged
+func repoRelativePath(importPath string, modules []goModule) string {
+	best := -1
+	for index, module := range modules {
+		modulePath := module.path()
+		if modulePath == "" || !strings.HasPrefix(importPath, modulePath+"/") {
+			continue
+		}
+		if best < 0 || len(modulePath) > len(modules[best].path()) {
+			best = index
+		}
+	}
+	if best < 0 {
+		return importPath
+	}
+	return path.Join(canonicalPath(modules[best].dir), strings.TrimPrefix(importPath, modules[best].path()+"/"))
+}
@@ -0,0 +1,108 @@
+package tools_build
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/spf13/afero"
+	"golang.org/x/mod/modfile"
+)
+
+// Copyright © 2026 Marc Johnson (marc.johnson27591@gmail.com)
+
+func parsedModule(t *testing.T, dir, content string) goModule {
+	t.Helper()
+	file, err := modfile.Parse("go.mod", []byte(content), nil)
+	if err != nil {
+		t.Fatalf("cannot parse %q: %v", content, err)
+	}
+	return goModule{dir: dir, file: file}
+}
+
+func Test_findModules(t *testing.T) {
+	originalBuildFS := BuildFS
+	originalCachedWorkingDir := CachedWorkingDir
+	defer func() {
+		BuildFS = originalBuildFS
+		CachedWorkingDir = originalCachedWorkingDir
+	}()
+	BuildFS = afero.NewMemMapFs()
+	_ = BuildFS.MkdirAll("good/build", dirMode)
+	_ = afero.WriteFile(BuildFS, "good/go.mod", []byte("module example.com/m\n\ngo 1.26\n"), fileMode)
+	_ = afero.WriteFile(BuildFS, "good/build/go.mod", []byte("module example.com/m/build\n"), fileMode)
+	_ = BuildFS.MkdirAll("bad", dirMode)
+	_ = afero.WriteFile(BuildFS, "bad/go.mod", []byte("modulo example.com/m\n"), fileMode)
+	tests := map[string]struct {
+		workDir   string
+		wantPaths []string
+		wantDirs  []string
+		wantOk    bool
+	}{
+		"no such dir": {
+			workDir: "missing",
+			wantOk:  false,
+		},
+		"unparseable": {
+			workDir: "bad",
+			wantOk:  false,
+		},
+		"good": {
+			workDir:   "good",
+			wantPaths: []string{"example.com/m", "example.com/m/build"},
+			wantDirs:  []string{"", "build"},
+			wantOk:    true,
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			CachedWorkingDir = tt.workDir
+			got, gotOk := findModules()
+			if gotOk != tt.wantOk {
+				t.Fatalf("findModules() ok = %v, want %v", gotOk, tt.wantOk)
+			}
+			var gotPaths, gotDirs []string
+			for _, module := range got {
+				gotPaths = append(gotPaths, module.path())
+				gotDirs = append(gotDirs, module.dir)
+			}
+			if !reflect.DeepEqual(gotPaths, tt.wantPaths) {
+				t.Errorf("findModules() paths = %v, want %v", gotPaths, tt.wantPaths)
+			}
+			if !reflect.DeepEqual(gotDirs, tt.wantDirs) {
+				t.Errorf("findModules() dirs = %v, want %v", gotDirs, tt.wantDirs)
+			}
+		})
+	}
+}
+
+func Test_repoRelativePath(t *testing.T) {
+	modules := []goModule{
+		parsedModule(t, "", "module example.com/m\n"),
+		parsedModule(t, "tools", "module example.com/m/tools\n"),
+		parsedModule(t, "empty", "go 1.26\n"),
+	}
+	tests := map[string]struct {
+		importPath string
+		want       string
+	}{
+		"root module": {
+			importPath: "example.com/m/a/a.go",
+			want:       "a/a.go",
+		},
+		"nested module": {
+			importPath: "example.com/m/tools/cmd/x.go",
+			want:       "tools/cmd/x.go",
+		},
+		"look-alike module": {
+			importPath: "example.com/mx/a.go",
+			want:       "example.com/mx/a.go",
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			if got := repoRelativePath(tt.importPath, modules); got != tt.want {
+				t.Errorf("repoRelativePath() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
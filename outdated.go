@@ -0,0 +1,206 @@
+package tools_build
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/goyek/goyek/v3"
+	"golang.org/x/mod/module"
+	"golang.org/x/mod/semver"
+)
+
+// Copyright © 2026 Marc Johnson (marc.johnson27591@gmail.com)
+
+// listedModule is the subset of the JSON written by 'go list -m -json' that
+// is of interest
+type listedModule struct {
+	Path     string
+	Version  string
+	Update   *listedModule
+	Main     bool
+	Indirect bool
+	Error    *struct{ Err string }
+}
+
+// outdatedDependency describes a dependency for which a newer version is
+// available
+type outdatedDependency struct {
+	module     string
+	dependency string
+	version    string
+	// update is the newest version with the same module path, if any
+	update string
+	// successor is the newest version of the next major version, such as
+	// example.com/m/v3 for example.com/m/v2, if any
+	successor string
+	indirect  bool
+}
+
+// minorVersionsBehind returns how many minor versions separate the current
+// version from the available update; an update to a different major version
+// is treated as infinitely far ahead
+func (od outdatedDependency) minorVersionsBehind() int {
+	if od.update == "" {
+		return 0
+	}
+	if semver.Major(od.version) != semver.Major(od.update) {
+		return math.MaxInt
+	}
+	return minorVersion(od.update) - minorVersion(od.version)
+}
+
+func minorVersion(version string) int {
+	_, minor, _ := strings.Cut(strings.TrimPrefix(semver.MajorMinor(version), semver.Major(version)), ".")
+	value, _ := strconv.Atoi(minor)
+	return value
+}
+
+// OutdatedDependencies lists, without modifying any go.mod file, the direct
+// and indirect dependencies of every module in the working directory tree for
+// which newer versions are available; for direct dependencies, it also looks
+// for a successor module with the next major version. Returns false on
+// failure, or if maxMinorVersionsBehind is not negative and any direct
+// dependency is more than maxMinorVersionsBehind minor versions behind its
+// available update
+func OutdatedDependencies(a *goyek.A, maxMinorVersionsBehind int) bool {
+	dirs, err := RelevantDirs(matchModuleFile)
+	if err != nil {
+		return false
+	}
+	outdated := make([]outdatedDependency, 0)
+	for _, dir := range dirs {
+		path := filepath.Join(WorkingDir(), dir)
+		fmt.Printf("%q: checking for dependency updates\n", path)
+		// with a go.work file present, every workspace module would be listed
+		// as a main module
+		state, stdout, stderr := cmdCapture(a, directedCommand{
+			command: "go list -m -u -json all",
+			dir:     path,
+			envVars: []EnvVarMemento{{Name: "GOWORK", Value: "off"}},
+		})
+		if !state {
+			if stderr != "" {
+				printIt(stderr)
+			}
+			return false
+		}
+		modules, ok := parseListedModules(stdout)
+		if !ok {
+			return false
+		}
+		mainModule := canonicalPath(dir)
+		for _, listed := range modules {
+			if listed.Main {
+				mainModule = listed.Path
+			}
+		}
+		for _, listed := range modules {
+			if listed.Main {
+				continue
+			}
+			dependency := outdatedDependency{
+				module:     mainModule,
+				dependency: listed.Path,
+				version:    listed.Version,
+				indirect:   listed.Indirect,
+			}
+			if listed.Update != nil {
+				dependency.update = listed.Update.Version
+			}
+			if !listed.Indirect {
+				dependency.successor = majorVersionSuccessor(a, path, listed.Path)
+			}
+			if dependency.update != "" || dependency.successor != "" {
+				outdated = append(outdated, dependency)
+			}
+		}
+	}
+	printIt(outdatedDependencyTable(outdated))
+	if maxMinorVersionsBehind < 0 {
+		return true
+	}
+	status := true
+	for _, dependency := range outdated {
+		if !dependency.indirect && dependency.minorVersionsBehind() > maxMinorVersionsBehind {
+			fmt.Fprintf(os.Stderr, "%s: %s %s is more than %d minor versions behind %s\n", dependency.module,
+				dependency.dependency, dependency.version, maxMinorVersionsBehind, dependency.update)
+			status = false
+		}
+	}
+	return status
+}
+
+// parseListedModules decodes the stream of JSON objects written by 'go list
+// -m -json'
+func parseListedModules(output string) ([]listedModule, bool) {
+	decoder := json.NewDecoder(strings.NewReader(output))
+	modules := make([]listedModule, 0)
+	for {
+		var listed listedModule
+		err := decoder.Decode(&listed)
+		if errors.Is(err, io.EOF) {
+			return modules, true
+		}
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "error %v parsing module list\n", err)
+			return nil, false
+		}
+		modules = append(modules, listed)
+	}
+}
+
+// majorVersionSuccessor looks up the latest version of the module path with
+// the next major version (example.com/m/v2 for example.com/m, example.com/m/v3
+// for example.com/m/v2), returning it as "path@version", or an empty string
+// if there is no such module
+func majorVersionSuccessor(a *goyek.A, dir, modulePath string) string {
+	prefix, pathMajor, ok := module.SplitPathVersion(modulePath)
+	if !ok || strings.HasPrefix(pathMajor, ".") {
+		return ""
+	}
+	major := 1
+	if pathMajor != "" {
+		major, _ = strconv.Atoi(strings.TrimPrefix(pathMajor, "/v"))
+	}
+	successor := fmt.Sprintf("%s/v%d", prefix, major+1)
+	state, stdout, _ := cmdCapture(a, directedCommand{
+		command: fmt.Sprintf("go list -m -e -json %s@latest", successor),
+		dir:     dir,
+	})
+	if !state {
+		return ""
+	}
+	modules, ok := parseListedModules(stdout)
+	if !ok || len(modules) != 1 || modules[0].Error != nil || modules[0].Version == "" {
+		return ""
+	}
+	return successor + "@" + modules[0].Version
+}
+
+// outdatedDependencyTable renders the outdated dependencies as a table
+func outdatedDependencyTable(outdated []outdatedDependency) string {
+	if len(outdated) == 0 {
+		return "all dependencies are up to date"
+	}
+	builder := &strings.Builder{}
+	w := tabwriter.NewWriter(builder, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "module\tdependency\tkind\tcurrent\tavailable\tnext major version")
+	for _, dependency := range outdated {
+		kind := "direct"
+		if dependency.indirect {
+			kind = "indirect"
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\n", dependency.module, dependency.dependency, kind,
+			dependency.version, orDash(dependency.update), orDash(dependency.successor))
+	}
+	_ = w.Flush()
+	return strings.TrimSuffix(builder.String(), "\n")
+}
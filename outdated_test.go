@@ -0,0 +1,234 @@
+package tools_build
+
+import (
+	"math"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/goyek/goyek/v3"
+	"github.com/spf13/afero"
+)
+
+// Copyright © 2026 Marc Johnson (marc.johnson27591@gmail.com)
+
+const listedModules = `{
+	"Path": "example.com/m",
+	"Main": true
+}
+{
+	"Path": "example.com/a",
+	"Version": "v1.2.0",
+	"Update": {"Path": "example.com/a", "Version": "v1.5.1"}
+}
+{
+	"Path": "example.com/b/v2",
+	"Version": "v2.0.0"
+}
+{
+	"Path": "example.com/c",
+	"Version": "v0.1.0",
+	"Update": {"Path": "example.com/c", "Version": "v0.9.0"},
+	"Indirect": true
+}
+{
+	"Path": "example.com/d",
+	"Version": "v1.0.0"
+}
+`
+
+func TestOutdatedDependencies(t *testing.T) {
+	originalBuildFS := BuildFS
+	originalCachedWorkingDir := CachedWorkingDir
+	originalCmdCapture := cmdCapture
+	defer func() {
+		BuildFS = originalBuildFS
+		CachedWorkingDir = originalCachedWorkingDir
+		cmdCapture = originalCmdCapture
+	}()
+	BuildFS = afero.NewMemMapFs()
+	_ = BuildFS.MkdirAll("work", dirMode)
+	_ = afero.WriteFile(BuildFS, "work/go.mod", []byte("module example.com/m\n"), fileMode)
+	CachedWorkingDir = "work"
+	tests := map[string]struct {
+		listSucceeds bool
+		listOutput   string
+		maxBehind    int
+		wantCommands []string
+		want         bool
+	}{
+		"list fails": {
+			listSucceeds: false,
+			maxBehind:    -1,
+			wantCommands: []string{"go list -m -u -json all"},
+			want:         false,
+		},
+		"bad output": {
+			listSucceeds: true,
+			listOutput:   "{",
+			maxBehind:    -1,
+			wantCommands: []string{"go list -m -u -json all"},
+			want:         false,
+		},
+		"report only": {
+			listSucceeds: true,
+			listOutput:   listedModules,
+			maxBehind:    -1,
+			wantCommands: []string{
+				"go list -m -u -json all",
+				"go list -m -e -json example.com/a/v2@latest",
+				"go list -m -e -json example.com/b/v3@latest",
+				"go list -m -e -json example.com/d/v2@latest",
+			},
+			want: true,
+		},
+		"direct dependency too far behind": {
+			listSucceeds: true,
+			listOutput:   listedModules,
+			maxBehind:    2,
+			wantCommands: []string{
+				"go list -m -u -json all",
+				"go list -m -e -json example.com/a/v2@latest",
+				"go list -m -e -json example.com/b/v3@latest",
+				"go list -m -e -json example.com/d/v2@latest",
+			},
+			want: false,
+		},
+		"within limit": {
+			listSucceeds: true,
+			listOutput:   listedModules,
+			maxBehind:    3,
+			wantCommands: []string{
+				"go list -m -u -json all",
+				"go list -m -e -json example.com/a/v2@latest",
+				"go list -m -e -json example.com/b/v3@latest",
+				"go list -m -e -json example.com/d/v2@latest",
+			},
+			want: true,
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			gotCommands := make([]string, 0)
+			cmdCapture = func(_ *goyek.A, dC directedCommand) (bool, string, string) {
+				gotCommands = append(gotCommands, dC.command)
+				if strings.HasSuffix(dC.command, "@latest") {
+					return true, `{"Path": "x", "Error": {"Err": "not found"}}`, ""
+				}
+				return tt.listSucceeds, tt.listOutput, "go: error"
+			}
+			if got := OutdatedDependencies(nil, tt.maxBehind); got != tt.want {
+				t.Errorf("OutdatedDependencies() = %v, want %v", got, tt.want)
+			}
+			if !reflect.DeepEqual(gotCommands, tt.wantCommands) {
+				t.Errorf("OutdatedDependencies() commands = %v, want %v", gotCommands, tt.wantCommands)
+			}
+		})
+	}
+}
+
+func Test_majorVersionSuccessor(t *testing.T) {
+	originalCmdCapture := cmdCapture
+	defer func() {
+		cmdCapture = originalCmdCapture
+	}()
+	tests := map[string]struct {
+		modulePath  string
+		state       bool
+		output      string
+		wantCommand string
+		want        string
+	}{
+		"gopkg.in": {
+			modulePath: "gopkg.in/yaml.v3",
+			want:       "",
+		},
+		"command fails": {
+			modulePath:  "example.com/a",
+			state:       false,
+			wantCommand: "go list -m -e -json example.com/a/v2@latest",
+			want:        "",
+		},
+		"no successor": {
+			modulePath:  "example.com/a/v2",
+			state:       true,
+			output:      `{"Path": "example.com/a/v3", "Error": {"Err": "no matching versions"}}`,
+			wantCommand: "go list -m -e -json example.com/a/v3@latest",
+			want:        "",
+		},
+		"successor": {
+			modulePath:  "example.com/a",
+			state:       true,
+			output:      `{"Path": "example.com/a/v2", "Version": "v2.3.0"}`,
+			wantCommand: "go list -m -e -json example.com/a/v2@latest",
+			want:        "example.com/a/v2@v2.3.0",
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			gotCommand := ""
+			cmdCapture = func(_ *goyek.A, dC directedCommand) (bool, string, string) {
+				gotCommand = dC.command
+				return tt.state, tt.output, ""
+			}
+			if got := majorVersionSuccessor(nil, "work", tt.modulePath); got != tt.want {
+				t.Errorf("majorVersionSuccessor() = %q, want %q", got, tt.want)
+			}
+			if gotCommand != tt.wantCommand {
+				t.Errorf("majorVersionSuccessor() command = %q, want %q", gotCommand, tt.wantCommand)
+			}
+		})
+	}
+}
+
+func Test_outdatedDependency_minorVersionsBehind(t *testing.T) {
+	tests := map[string]struct {
+		version string
+		update  string
+		want    int
+	}{
+		"no update":     {version: "v1.2.3", want: 0},
+		"patch":         {version: "v1.2.3", update: "v1.2.4", want: 0},
+		"minor":         {version: "v1.2.3", update: "v1.5.0", want: 3},
+		"v0":            {version: "v0.1.0", update: "v0.10.2", want: 9},
+		"pseudoversion": {version: "v0.0.0-20240101000000-abcdefabcdef", update: "v0.2.0", want: 2},
+		"incompatible":  {version: "v1.0.0", update: "v2.0.0+incompatible", want: math.MaxInt},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			od := outdatedDependency{version: tt.version, update: tt.update}
+			if got := od.minorVersionsBehind(); got != tt.want {
+				t.Errorf("minorVersionsBehind() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_outdatedDependencyTable(t *testing.T) {
+	tests := map[string]struct {
+		outdated []outdatedDependency
+		want     string
+	}{
+		"none": {
+			outdated: nil,
+			want:     "all dependencies are up to date",
+		},
+		"some": {
+			outdated: []outdatedDependency{
+				{module: "m", dependency: "example.com/a", version: "v1.0.0", update: "v1.1.0", successor: "example.com/a/v2@v2.0.0"},
+				{module: "m", dependency: "example.com/c", version: "v0.1.0", update: "v0.2.0", indirect: true},
+			},
+			want: "" +
+				"module  dependency     kind      current  available  next major version\n" +
+				"m       example.com/a  direct    v1.0.0   v1.1.0     example.com/a/v2@v2.0.0\n" +
+				"m       example.com/c  indirect  v0.1.0   v0.2.0     -",
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			if got := outdatedDependencyTable(tt.outdated); got != tt.want {
+				t.Errorf("outdatedDependencyTable() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,167 @@
+package tools_build
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+
+	"github.com/spf13/afero"
+)
+
+// Copyright © 2026 Marc Johnson (marc.johnson27591@gmail.com)
+
+// SARIFFlag is a flag that names a file into which Lint, NilAway, Deadcode and VulnerabilityCheck merge their
+// findings in SARIF format
+var SARIFFlag = flag.String(
+	"sarif",
+	"",
+	"set to the name of a file into which static analysis findings will be merged in SARIF format")
+
+const (
+	sarifVersion = "2.1.0"
+	sarifSchema  = "https://json.schemastore.org/sarif-2.1.0.json"
+	// sarifSourceRoot is the base ID that artifact locations are relative to
+	sarifSourceRoot = "%SRCROOT%"
+)
+
+type sarifLog struct {
+	Version string     `json:"version"`
+	Schema  string     `json:"$schema"`
+	Runs    []sarifRun `json:"runs"`
+}
+
+type sarifRun struct {
+	Tool    sarifTool     `json:"tool"`
+	Results []sarifResult `json:"results"`
+}
+
+type sarifTool struct {
+	Driver sarifDriver `json:"driver"`
+}
+
+type sarifDriver struct {
+	Name  string      `json:"name"`
+	Rules []sarifRule `json:"rules,omitempty"`
+}
+
+type sarifRule struct {
+	ID string `json:"id"`
+}
+
+type sarifResult struct {
+	RuleID    string          `json:"ruleId"`
+	Level     string          `json:"level"`
+	Message   sarifMessage    `json:"message"`
+	Locations []sarifLocation `json:"locations,omitempty"`
+}
+
+type sarifMessage struct {
+	Text string `json:"text"`
+}
+
+type sarifLocation struct {
+	PhysicalLocation sarifPhysicalLocation `json:"physicalLocation"`
+}
+
+type sarifPhysicalLocation struct {
+	ArtifactLocation sarifArtifactLocation `json:"artifactLocation"`
+	Region           *sarifRegion          `json:"region,omitempty"`
+}
+
+type sarifArtifactLocation struct {
+	URI       string `json:"uri"`
+	URIBaseID string `json:"uriBaseId"`
+}
+
+type sarifRegion struct {
+	StartLine   int `json:"startLine"`
+	StartColumn int `json:"startColumn,omitempty"`
+}
+
+// WriteSARIF writes the findings, in SARIF 2.1.0 format, to sarifFile, which
+// is located relative to WorkingDir(); each tool's findings form a separate
+// run. If the file already exists, its runs for other tools are kept, so that
+// the findings of several tools can be merged into a single file; runs for the
+// same tools are replaced. A tool with no findings is named by tools, so that
+// its earlier findings, if any, are removed. Returns false on failure
+func WriteSARIF(sarifFile string, tools []string, findings []Finding) bool {
+	if isIllegalFileName(sarifFile) {
+		fmt.Fprintf(os.Stderr, "cannot accept %q as a valid file name to which SARIF data can be written\n", sarifFile)
+		return false
+	}
+	log := sarifLog{Version: sarifVersion, Schema: sarifSchema}
+	fileName := filepath.Join(WorkingDir(), sarifFile)
+	if exists, _ := afero.Exists(BuildFS, fileName); exists {
+		content, err := afero.ReadFile(BuildFS, fileName)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "error %v reading %q\n", err, fileName)
+			return false
+		}
+		if err = json.Unmarshal(content, &log); err != nil {
+			fmt.Fprintf(os.Stderr, "error %v parsing %q\n", err, fileName)
+			return false
+		}
+	}
+	runs := map[string]sarifRun{}
+	for _, run := range log.Runs {
+		runs[run.Tool.Driver.Name] = run
+	}
+	for _, tool := range tools {
+		runs[tool] = sarifRun{Tool: sarifTool{Driver: sarifDriver{Name: tool}}, Results: []sarifResult{}}
+	}
+	for tool, run := range sarifRuns(findings) {
+		runs[tool] = run
+	}
+	log.Runs = make([]sarifRun, 0, len(runs))
+	for _, tool := range slices.Sorted(maps.Keys(runs)) {
+		log.Runs = append(log.Runs, runs[tool])
+	}
+	content, err := json.MarshalIndent(log, "", "  ")
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "error %v creating SARIF data\n", err)
+		return false
+	}
+	fmt.Printf("writing SARIF data to %q\n", sarifFile)
+	return writeWorkingFile(sarifFile, append(content, '\n'))
+}
+
+// sarifRuns groups the findings by tool, creating a run for each tool
+func sarifRuns(findings []Finding) map[string]sarifRun {
+	runs := map[string]sarifRun{}
+	rules := map[string]map[string]bool{}
+	for _, finding := range findings {
+		run, found := runs[finding.Tool]
+		if !found {
+			run = sarifRun{Tool: sarifTool{Driver: sarifDriver{Name: finding.Tool}}, Results: []sarifResult{}}
+			rules[finding.Tool] = map[string]bool{}
+		}
+		rules[finding.Tool][finding.Rule] = true
+		result := sarifResult{
+			RuleID:  finding.Rule,
+			Level:   finding.Severity,
+			Message: sarifMessage{Text: finding.Message},
+		}
+		if finding.File != "" {
+			location := sarifLocation{PhysicalLocation: sarifPhysicalLocation{
+				ArtifactLocation: sarifArtifactLocation{URI: finding.File, URIBaseID: sarifSourceRoot},
+			}}
+			if finding.Line > 0 {
+				location.PhysicalLocation.Region = &sarifRegion{StartLine: finding.Line, StartColumn: finding.Column}
+			}
+			result.Locations = []sarifLocation{location}
+		}
+		run.Results = append(run.Results, result)
+		runs[finding.Tool] = run
+	}
+	for tool, run := range runs {
+		for _, rule := range slices.Sorted(maps.Keys(rules[tool])) {
+			run.Tool.Driver.Rules = append(run.Tool.Driver.Rules, sarifRule{ID: rule})
+		}
+		runs[tool] = run
+	}
+	return runs
+}
@@ -0,0 +1,191 @@
+package tools_build
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/goyek/goyek/v3"
+	"github.com/goyek/x/cmd"
+	"github.com/spf13/afero"
+)
+
+// Copyright © 2026 Marc Johnson (marc.johnson27591@gmail.com)
+
+func readSARIFRuns(t *testing.T, fileName string) map[string][]sarifResult {
+	t.Helper()
+	content, err := afero.ReadFile(BuildFS, fileName)
+	if err != nil {
+		t.Fatalf("cannot read %q: %v", fileName, err)
+	}
+	var log sarifLog
+	if err = json.Unmarshal(content, &log); err != nil {
+		t.Fatalf("cannot parse %q: %v", fileName, err)
+	}
+	if log.Version != "2.1.0" {
+		t.Errorf("SARIF version = %q, want %q", log.Version, "2.1.0")
+	}
+	runs := map[string][]sarifResult{}
+	for _, run := range log.Runs {
+		runs[run.Tool.Driver.Name] = run.Results
+	}
+	return runs
+}
+
+func TestWriteSARIF(t *testing.T) {
+	originalBuildFS := BuildFS
+	originalCachedWorkingDir := CachedWorkingDir
+	defer func() {
+		BuildFS = originalBuildFS
+		CachedWorkingDir = originalCachedWorkingDir
+	}()
+	CachedWorkingDir = "work"
+	existing := `{"version": "2.1.0", "runs": [
+		{"tool": {"driver": {"name": "nilaway"}}, "results": [{"ruleId": "nilaway", "level": "error", "message": {"text": "old"}}]},
+		{"tool": {"driver": {"name": "gocritic"}}, "results": [{"ruleId": "dupImport", "level": "warning", "message": {"text": "old"}}]}
+	]}`
+	findings := []Finding{
+		{Tool: "gocritic", File: "a.go", Line: 3, Column: 2, Rule: "hugeParam", Message: "too big", Severity: SeverityWarning},
+		{Tool: "govulncheck", Rule: "GO-1", Message: "vulnerable", Severity: SeverityWarning},
+	}
+	tests := map[string]struct {
+		fileName string
+		existing string
+		tools    []string
+		want     bool
+		wantRuns map[string][]sarifResult
+	}{
+		"bad file name": {
+			fileName: "../findings.sarif",
+			want:     false,
+		},
+		"unparseable existing file": {
+			fileName: "findings.sarif",
+			existing: "{",
+			want:     false,
+		},
+		"new file": {
+			fileName: "findings.sarif",
+			tools:    []string{"deadcode"},
+			want:     true,
+			wantRuns: map[string][]sarifResult{
+				"deadcode": {},
+				"gocritic": {{
+					RuleID:  "hugeParam",
+					Level:   "warning",
+					Message: sarifMessage{Text: "too big"},
+					Locations: []sarifLocation{{PhysicalLocation: sarifPhysicalLocation{
+						ArtifactLocation: sarifArtifactLocation{URI: "a.go", URIBaseID: "%SRCROOT%"},
+						Region:           &sarifRegion{StartLine: 3, StartColumn: 2},
+					}}},
+				}},
+				"govulncheck": {{RuleID: "GO-1", Level: "warning", Message: sarifMessage{Text: "vulnerable"}}},
+			},
+		},
+		"merged": {
+			fileName: "findings.sarif",
+			existing: existing,
+			want:     true,
+			wantRuns: map[string][]sarifResult{
+				"nilaway": {{RuleID: "nilaway", Level: "error", Message: sarifMessage{Text: "old"}}},
+				"gocritic": {{
+					RuleID:  "hugeParam",
+					Level:   "warning",
+					Message: sarifMessage{Text: "too big"},
+					Locations: []sarifLocation{{PhysicalLocation: sarifPhysicalLocation{
+						ArtifactLocation: sarifArtifactLocation{URI: "a.go", URIBaseID: "%SRCROOT%"},
+						Region:           &sarifRegion{StartLine: 3, StartColumn: 2},
+					}}},
+				}},
+				"govulncheck": {{RuleID: "GO-1", Level: "warning", Message: sarifMessage{Text: "vulnerable"}}},
+			},
+		},
+		"tool without findings replaced": {
+			fileName: "findings.sarif",
+			existing: existing,
+			tools:    []string{"nilaway"},
+			want:     true,
+			wantRuns: map[string][]sarifResult{
+				"nilaway": {},
+				"gocritic": {{
+					RuleID:  "hugeParam",
+					Level:   "warning",
+					Message: sarifMessage{Text: "too big"},
+					Locations: []sarifLocation{{PhysicalLocation: sarifPhysicalLocation{
+						ArtifactLocation: sarifArtifactLocation{URI: "a.go", URIBaseID: "%SRCROOT%"},
+						Region:           &sarifRegion{StartLine: 3, StartColumn: 2},
+					}}},
+				}},
+				"govulncheck": {{RuleID: "GO-1", Level: "warning", Message: sarifMessage{Text: "vulnerable"}}},
+			},
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			BuildFS = afero.NewMemMapFs()
+			_ = BuildFS.MkdirAll("work", dirMode)
+			if tt.existing != "" {
+				_ = afero.WriteFile(BuildFS, "work/"+tt.fileName, []byte(tt.existing), fileMode)
+			}
+			if got := WriteSARIF(tt.fileName, tt.tools, findings); got != tt.want {
+				t.Errorf("WriteSARIF() = %v, want %v", got, tt.want)
+			}
+			if tt.wantRuns != nil {
+				if got := readSARIFRuns(t, "work/"+tt.fileName); !reflect.DeepEqual(got, tt.wantRuns) {
+					t.Errorf("WriteSARIF() runs = %v, want %v", got, tt.wantRuns)
+				}
+			}
+		})
+	}
+}
+
+func TestStaticAnalysisSARIF(t *testing.T) {
+	originalBuildFS := BuildFS
+	originalCachedWorkingDir := CachedWorkingDir
+	originalExecFn := ExecFn
+	originalCmdCapture := cmdCapture
+	originalSARIFFlag := SARIFFlag
+	defer func() {
+		BuildFS = originalBuildFS
+		CachedWorkingDir = originalCachedWorkingDir
+		ExecFn = originalExecFn
+		cmdCapture = originalCmdCapture
+		SARIFFlag = originalSARIFFlag
+	}()
+	BuildFS = afero.NewMemMapFs()
+	_ = BuildFS.MkdirAll("work", dirMode)
+	CachedWorkingDir = "work"
+	sarifFile := "findings.sarif"
+	SARIFFlag = &sarifFile
+	ExecFn = func(_ *goyek.A, _ string, _ ...cmd.Option) bool {
+		return true
+	}
+	outputs := map[string]struct {
+		state  bool
+		stdout string
+	}{
+		"gocritic check -enableAll ./...": {state: false, stdout: "a.go:1:1: dupImport: duplicate import\n"},
+		"nilaway -json ./...":             {state: true, stdout: "{}"},
+		"deadcode -json -test .": {state: true, stdout: `[{"Name": "m", "Path": "example.com/m", "Funcs": [
+			{"Name": "unused", "Position": {"File": "a.go", "Line": 7, "Col": 6}}]}]`},
+	}
+	cmdCapture = func(_ *goyek.A, dC directedCommand) (bool, string, string) {
+		output := outputs[dC.command]
+		return output.state, output.stdout, ""
+	}
+	if got := Lint(nil); got {
+		t.Errorf("Lint() = %v, want %v", got, false)
+	}
+	if got := NilAway(nil); !got {
+		t.Errorf("NilAway() = %v, want %v", got, true)
+	}
+	if got := Deadcode(nil); !got {
+		t.Errorf("Deadcode() = %v, want %v", got, true)
+	}
+	runs := readSARIFRuns(t, "work/findings.sarif")
+	for tool, want := range map[string]int{"gocritic": 1, "nilaway": 0, "deadcode": 1} {
+		if got := len(runs[tool]); got != want {
+			t.Errorf("%s results = %d, want %d", tool, got, want)
+		}
+	}
+}
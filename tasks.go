@@ -7,6 +7,7 @@ import (
 	"os"
 	"path/filepath"
 	"strings"
+	"time"
 
 	"github.com/goyek/goyek/v3"
 	"github.com/goyek/x/cmd"
@@ -15,6 +16,8 @@ import (
 
 // Copyright © 2026 Marc Johnson (marc.johnson27591@gmail.com)
 
+const defaultDeadcodeTemplate = `{{println .Path}}{{range .Funcs}}{{printf "\t%s\t%s\n" .Position .Name}}{{end}}{{println}}`
+
 var (
 	// AggressiveFlag is a flag for the UpdateDependencies function to more aggressively get updates
 	AggressiveFlag = flag.Bool(
@@ -40,17 +43,42 @@ var (
 	// TemplateFlag is a flag that allows the caller to change the format template used by the deadcode command
 	TemplateFlag = flag.String(
 		"template",
-		`{{println .Path}}{{range .Funcs}}{{printf "\t%s\t%s\n" .Position .Name}}{{end}}{{println}}`,
+		defaultDeadcodeTemplate,
 		"set to change the template used to format dead code analysis (ignored if -noformat is true)")
 	// ExecFn is the goyek Exec function. set as a variable so that unit tests can override
 	ExecFn = cmd.Exec
 	// ExitFn is the os.Exit function, set as a variable so that unit tests can override
 	ExitFn = os.Exit
+	// NowFn is the time.Now function, set as a variable so that unit tests can override
+	NowFn = time.Now
+	// JUnitFlag is a flag that allows the caller to name a file to which the UnitTests function writes a JUnit XML
+	// report
+	JUnitFlag = flag.String(
+		"junit",
+		"",
+		"set to the name of a file to which a JUnit XML report of the unit test results will be written")
+	// DependencyReportFlag is a flag that allows the caller to name a file to which the UpdateDependencies function
+	// writes a markdown report of the dependency changes
+	DependencyReportFlag = flag.String(
+		"dependencyreport",
+		"",
+		"set to the name of a file to which a markdown report of dependency changes will be written")
 )
 
 // Deadcode runs dead code analysis on the source code after making sure that the deadcode tool is
-// up-to-date; returns false on failure
+// up-to-date, and reports the unreachable functions that are not allowlisted (see DeadcodeWithOptions); returns false
+// on failure, or if the -deadcodefail flag is set and any unreachable function is not allowlisted. If the -noformat
+// flag is set, or the -template flag is changed, and neither the -sarif flag nor the -deadcodefail flag is set, the
+// deadcode output is printed instead, formatted as directed
 func Deadcode(a *goyek.A) bool {
+	options := FlagDeadcodeOptions()
+	if !options.Fail && *SARIFFlag == "" && (*NoFormatFlag || *TemplateFlag != defaultDeadcodeTemplate) {
+		return rawDeadcode(a)
+	}
+	return DeadcodeWithOptions(a, options)
+}
+
+func rawDeadcode(a *goyek.A) bool {
 	if !Install(a, "golang.org/x/tools/cmd/deadcode") {
 		return false
 	}
@@ -119,10 +147,19 @@ func Generate(a *goyek.A) bool {
 }
 
 // GenerateCoverageReport runs the unit tests, generating a coverage profile; if
-// the unit tests all succeed, generates the report as HTML to be displayed in
-// the current browser window. Returns false if either the unit tests or the
-// coverage report display fails
+// the unit tests all succeed, generates the report as directed by the command
+// line flags (see FlagCoverageReportOptions); by default, the report is
+// generated as HTML to be displayed in the current browser window. Returns
+// false if either the unit tests or the coverage report fails
 func GenerateCoverageReport(a *goyek.A, coverageDataFile string) bool {
+	return GenerateCoverageReportWithOptions(a, coverageDataFile, FlagCoverageReportOptions())
+}
+
+// GenerateCoverageReportWithOptions runs the unit tests, generating a coverage
+// profile; if the unit tests all succeed, generates the report as directed by
+// the options (see CoverageReport). Returns false if either the unit tests or
+// the coverage report fails
+func GenerateCoverageReportWithOptions(a *goyek.A, coverageDataFile string, options CoverageReportOptions) bool {
 	if isIllegalFileName(coverageDataFile) {
 		fmt.Fprintf(os.Stderr, "cannot accept %q as a valid file name to which coverage data can be written", coverageDataFile)
 		return false
@@ -131,8 +168,7 @@ func GenerateCoverageReport(a *goyek.A, coverageDataFile string) bool {
 	if !RunCommand(a, fmt.Sprintf("go test -coverprofile=%s ./...", coverageDataFile)) {
 		return false
 	}
-	fmt.Printf("displaying coverage report from %q\n", coverageDataFile)
-	return RunCommand(a, fmt.Sprintf("go tool cover -html=%s", coverageDataFile))
+	return CoverageReport(a, coverageDataFile, options)
 }
 
 // GenerateDocumentation generates documentation of the code, outputting it to
@@ -167,13 +203,18 @@ func GenerateDocumentation(a *goyek.A, excludedDirs []string) bool {
 
 var cmdOutput = commandOutput
 
-// GoFix runs the go fix command and displays the changes, if any
+// GoFix runs the go fix command, reporting the changes, if any, as findings
+// (see reportFindings) followed by the changes themselves, and then applying
+// them
 func GoFix(a *goyek.A) bool {
 	printIt("running go fix")
 	state, diffs := cmdOutput(a, "go fix -diff ./...")
 	if !state {
 		return false
 	}
+	if !reportFindings("gofix", parseGoFixDiff(diffs)) {
+		return false
+	}
 	status := true
 	if diffs == "" {
 		printIt("no differences found")
@@ -186,6 +227,29 @@ func GoFix(a *goyek.A) bool {
 	return status
 }
 
+var cmdCapture = captureCommand
+
+// captureCommand runs the directed command, returning its state and what it
+// wrote to stdout and stderr; unlike commandOutput, the output is returned
+// whether or not the command succeeds
+func captureCommand(a *goyek.A, dC directedCommand) (state bool, stdout, stderr string) {
+	stdoutBuffer := &bytes.Buffer{}
+	stderrBuffer := &bytes.Buffer{}
+	options := make([]cmd.Option, 3)
+	options[0] = cmd.Dir(dC.dir)
+	options[1] = cmd.Stderr(stderrBuffer)
+	options[2] = cmd.Stdout(stdoutBuffer)
+	savedEnvVars, envVarsOK := SetupEnvVars(dC.envVars)
+	state = envVarsOK
+	if state {
+		defer RestoreEnvVars(savedEnvVars)
+		state = ExecFn(a, dC.command, options...)
+	}
+	stdout = EatTrailingEOL(stdoutBuffer.String())
+	stderr = EatTrailingEOL(stderrBuffer.String())
+	return
+}
+
 func commandOutput(a *goyek.A, command string) (state bool, s string) {
 	outputBuffer := &bytes.Buffer{}
 	options := make([]cmd.Option, 3)
@@ -206,24 +270,32 @@ func Install(a *goyek.A, packageName string) bool {
 	return RunCommand(a, fmt.Sprintf("go install -v %s@latest", packageName))
 }
 
-// Lint runs lint on the source code after making sure that the lint tool is up-to-date;
-// returns false on failure
+// Lint runs the linters selected by the -linters flag (see FlagLinters), gocritic by default, after making sure that
+// they are up-to-date, and reports their findings (see reportFindings); returns false on failure, or if there are any
+// findings that are not in the lint baseline file named by the -lintbaseline flag. If the -lintbaselineupdate flag is
+// set, the baseline is replaced with the findings instead (see UpdateLintBaseline)
 func Lint(a *goyek.A) bool {
-	if !Install(a, "github.com/go-critic/go-critic/cmd/gocritic") {
-		return false
-	}
-	printIt("linting source code")
-	return RunCommand(a, "gocritic check -enableAll ./...")
+	selected, ok := FlagLinters()
+	return ok && LintWith(a, selected...)
+}
+
+// LintWith works like Lint, running the specified linters
+func LintWith(a *goyek.A, selected ...Linter) bool {
+	return lintAgainstBaseline(a, selected)
+}
+
+// LintWithOptions works like Lint, running only gocritic, with the checkers
+// selected by the options
+func LintWithOptions(a *goyek.A, options LintOptions) bool {
+	return LintWith(a, GocriticLinter{Options: options})
 }
 
 // NilAway runs the nilaway tool, which attempts, via static analysis, to detect
-// potential nil access errors; returns false on errors
+// potential nil access errors, and reports its findings (see reportFindings);
+// returns false on errors, or if there are any findings
 func NilAway(a *goyek.A) bool {
-	if !Install(a, "go.uber.org/nilaway/cmd/nilaway") {
-		return false
-	}
-	printIt("running nilaway analysis")
-	return RunCommand(a, "nilaway ./...")
+	findings, ok := NilAwayFindings(a)
+	return ok && reportFindings("nilaway", findings) && len(findings) == 0
 }
 
 // RunCommand runs a command and displays all of its output; returns true on
@@ -247,53 +319,188 @@ func TaskDisabled(taskName string) (disabled bool) {
 	return
 }
 
-// UnitTests runs all unit tests, with code coverage enabled; returns false on
-// failure
+// UnitTests runs all unit tests, with code coverage enabled, as directed by the
+// command line flags (see FlagTestOptions); returns false on failure
 func UnitTests(a *goyek.A) bool {
-	printIt("running all unit tests")
-	return RunCommand(a, "go test -cover ./...")
+	return UnitTestsWithOptions(a, FlagTestOptions())
+}
+
+// UnitTestsWithOptions runs the unit tests, with code coverage enabled, as
+// directed by the options; if options.JUnitReport is set, the results are also
+// written as a JUnit XML report to the named file, if options.Retries is set,
+// failed tests are rerun, and if options.Coverage is set, the coverage
+// thresholds are enforced (see TestOptions). Returns false on failure
+func UnitTestsWithOptions(a *goyek.A, options TestOptions) bool {
+	if profile := options.coverProfile(); profile != "" && isIllegalFileName(profile) {
+		fmt.Fprintf(os.Stderr, "cannot accept %q as a valid file name to which coverage data can be written", profile)
+		return false
+	}
+	if !runUnitTests(a, options) {
+		return false
+	}
+	if options.Coverage != nil {
+		printIt("checking code coverage in", options.coverProfile())
+		return CoverageGate(options.coverProfile(), *options.Coverage)
+	}
+	return true
 }
 
 // UpdateDependencies updates module dependencies and prunes the modified go.mod
-// and go.sum files
+// and go.sum files, then reports the dependencies that were updated, added or
+// removed in each module; if the -dependencyreport flag names a file, the
+// report is also written to that file as markdown. The update policy is
+// specified by the command line flags (see FlagUpdatePolicy)
 func UpdateDependencies(a *goyek.A) bool {
+	return UpdateDependenciesWithPolicy(a, FlagUpdatePolicy())
+}
+
+// UpdateDependenciesWithPolicy works like UpdateDependencies, updating the
+// dependencies selected by the policy. If the policy calls for verification,
+// a module whose build or tests fail after its update has its go.mod and
+// go.sum files restored, its attempted changes are reported as reverted, and
+// false is returned
+func UpdateDependenciesWithPolicy(a *goyek.A, policy UpdatePolicy) bool {
+	reportFile := *DependencyReportFlag
+	if reportFile != "" && isIllegalFileName(reportFile) {
+		fmt.Fprintf(os.Stderr, "cannot accept %q as a valid file name to which the dependency report can be written\n", reportFile)
+		return false
+	}
+	if !policy.validate() {
+		return false
+	}
 	dirs, err := RelevantDirs(matchModuleFile)
 	if err != nil {
 		return false
 	}
-	getCommand := directedCommand{command: "go get -u ./..."}
-	if *AggressiveFlag {
-		getCommand.envVars = append(getCommand.envVars, EnvVarMemento{
+	var envVars []EnvVarMemento
+	if policy.Aggressive {
+		envVars = append(envVars, EnvVarMemento{
 			Name:  "GOPROXY",
 			Value: "direct",
 			Unset: false,
 		})
 	}
 	tidyCommand := directedCommand{command: "go mod tidy"}
+	changes := make([]dependencyChange, 0)
+	reverted := make([]dependencyChange, 0)
 	for _, dir := range dirs {
 		path := filepath.Join(WorkingDir(), dir)
-		getCommand.dir = path
-		tidyCommand.dir = path
-		fmt.Printf("%q: updating dependencies\n", path)
-		if !getCommand.execute(a) {
+		before, ok := takeModuleSnapshot(dir)
+		if !ok {
+			return false
+		}
+		getCommands, ok := policy.updateCommands(before)
+		if !ok {
 			return false
 		}
+		fmt.Printf("%q: updating dependencies\n", path)
+		if len(getCommands) == 0 {
+			printIt("no dependencies selected for update")
+		}
+		for _, command := range getCommands {
+			getCommand := directedCommand{command: command, dir: path, envVars: envVars}
+			if !getCommand.execute(a) {
+				return false
+			}
+		}
+		tidyCommand.dir = path
 		fmt.Printf("%q: pruning go.mod and go.sum\n", path)
 		if !tidyCommand.execute(a) {
 			return false
 		}
+		after, ok := takeModuleSnapshot(dir)
+		if !ok {
+			return false
+		}
+		moduleChanges, ok := dependencyChanges(before, after)
+		if !ok {
+			return false
+		}
+		if policy.Verify && after.changed(before) && !verifyModule(a, path) {
+			fmt.Printf("%q: reverting dependency updates\n", path)
+			if !before.restore() {
+				return false
+			}
+			reverted = append(reverted, moduleChanges...)
+			continue
+		}
+		changes = append(changes, moduleChanges...)
+	}
+	printIt(dependencyChangeTable(changes, reverted))
+	if reportFile != "" {
+		fmt.Printf("writing dependency report to %q\n", reportFile)
+		if !writeWorkingFile(reportFile, []byte(dependencyChangeMarkdown(changes, reverted))) {
+			return false
+		}
+	}
+	return len(reverted) == 0
+}
+
+// verifyModule builds and tests the module in the specified directory
+func verifyModule(a *goyek.A, path string) bool {
+	fmt.Printf("%q: verifying dependency updates\n", path)
+	for _, command := range []string{"go build ./...", "go test ./..."} {
+		if !(directedCommand{command: command, dir: path}).execute(a) {
+			return false
+		}
 	}
 	return true
 }
 
 // VulnerabilityCheck runs the govulncheck tool, which checks for unresolved
-// known vulnerabilities in the libraries used; returns false on failure
+// known vulnerabilities in the libraries used, and reports them (see
+// VulnerabilityFindings). Vulnerabilities listed in the suppression file named
+// by the -vulnsuppressions flag (see VulnerabilitySuppression) are reported,
+// but do not cause a failure until their suppressions expire. Returns false on
+// failure, if the code calls any vulnerable symbol that is not suppressed, or
+// if any suppression has expired. The vulnerabilities are recorded as findings
+// (see FindingsOf) and, if the -sarif flag names a file, merged into that file
+// (see WriteSARIF)
 func VulnerabilityCheck(a *goyek.A) bool {
-	if !Install(a, "golang.org/x/vuln/cmd/govulncheck") {
+	suppressions, ok := readVulnerabilitySuppressions(*VulnerabilitySuppressionsFlag)
+	if !ok {
+		return false
+	}
+	vulnerabilities, ok := VulnerabilityFindings(a)
+	if !ok {
+		return false
+	}
+	findings := vulnerabilityFindings(vulnerabilities)
+	reportedFindings["govulncheck"] = findings
+	if *SARIFFlag != "" && !WriteSARIF(*SARIFFlag, []string{"govulncheck"}, findings) {
 		return false
 	}
-	printIt("running vulnerability checks")
-	return RunCommand(a, "govulncheck -show verbose ./...")
+	called, current := applyVulnerabilitySuppressions(vulnerabilities, suppressions)
+	if called != 0 {
+		fmt.Fprintf(os.Stderr, "%d called vulnerabilities found\n", called)
+		return false
+	}
+	return current
+}
+
+func runUnitTests(a *goyek.A, options TestOptions) bool {
+	if options.JUnitReport == "" && options.Retries <= 0 {
+		printIt("running unit tests")
+		return RunCommand(a, options.command(false))
+	}
+	if options.JUnitReport != "" && isIllegalFileName(options.JUnitReport) {
+		fmt.Fprintf(os.Stderr, "cannot accept %q as a valid file name to which a JUnit report can be written", options.JUnitReport)
+		return false
+	}
+	if options.FlakyReport != "" && isIllegalFileName(options.FlakyReport) {
+		fmt.Fprintf(os.Stderr, "cannot accept %q as a valid file name to which a flaky test report can be written", options.FlakyReport)
+		return false
+	}
+	printIt("running unit tests")
+	state, results := runJSONTests(a, options.command(true))
+	if !state && options.Retries > 0 {
+		state = retryFailedTests(a, options, results)
+	}
+	if options.JUnitReport != "" {
+		printIt("writing JUnit report to", options.JUnitReport)
+		state = writeJUnitReport(options.JUnitReport, results) && state
+	}
+	return state
 }
 
 type directedCommand struct {
@@ -317,3 +524,12 @@ func (dC directedCommand) execute(a *goyek.A) bool {
 	}
 	return state
 }
+
+// quoteArg quotes a command line argument, if necessary, so that it survives
+// the shell-like parsing performed by ExecFn intact
+func quoteArg(s string) string {
+	if s != "" && strings.Trim(s, "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789-_.,/:=@+%") == "" {
+		return s
+	}
+	return "'" + strings.ReplaceAll(s, "'", `'"'"'`) + "'"
+}
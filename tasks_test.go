@@ -1,10 +1,12 @@
 package tools_build
 
 import (
+	"os/exec"
 	"path/filepath"
 	"reflect"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/goyek/goyek/v3"
 	"github.com/goyek/x/cmd"
@@ -56,7 +58,7 @@ func TestDeadcode(t *testing.T) {
 			templateFlag:     `{{println .Path}}{{range .Funcs}}{{printf "\t%s\t%s\n" .Position .Name}}{{end}}{{println}}`,
 			wantCommands: []string{
 				"go install -v golang.org/x/tools/cmd/deadcode@latest",
-				`deadcode -f='{{println .Path}}{{range .Funcs}}{{printf "\t%s\t%s\n" .Position .Name}}{{end}}{{println}}' -test .`,
+				"deadcode -json -test .",
 			},
 			want: false,
 		},
@@ -68,7 +70,7 @@ func TestDeadcode(t *testing.T) {
 			templateFlag:     `{{println .Path}}{{range .Funcs}}{{printf "\t%s\t%s\n" .Position .Name}}{{end}}{{println}}`,
 			wantCommands: []string{
 				"go install -v golang.org/x/tools/cmd/deadcode@latest",
-				`deadcode -f='{{println .Path}}{{range .Funcs}}{{printf "\t%s\t%s\n" .Position .Name}}{{end}}{{println}}' -test .`,
+				"deadcode -json -test .",
 			},
 			want: true,
 		},
@@ -92,7 +94,7 @@ func TestDeadcode(t *testing.T) {
 			templateFlag:     `{{println .Path}}{{range .Funcs}}{{printf "\t%s\t%s\n" .Position .Name}}{{end}}{{println}}`,
 			wantCommands: []string{
 				"go install -v golang.org/x/tools/cmd/deadcode@latest",
-				`deadcode -f='{{println .Path}}{{range .Funcs}}{{printf "\t%s\t%s\n" .Position .Name}}{{end}}{{println}}' .`,
+				"deadcode -json .",
 			},
 			want: true,
 		},
@@ -642,7 +644,7 @@ func TestNilAway(t *testing.T) {
 			nilawaySucceeds: false,
 			wantCommands: []string{
 				"go install -v go.uber.org/nilaway/cmd/nilaway@latest",
-				"nilaway ./...",
+				"nilaway -json ./...",
 			},
 			want: false,
 		},
@@ -651,7 +653,7 @@ func TestNilAway(t *testing.T) {
 			nilawaySucceeds: true,
 			wantCommands: []string{
 				"go install -v go.uber.org/nilaway/cmd/nilaway@latest",
-				"nilaway ./...",
+				"nilaway -json ./...",
 			},
 			want: true,
 		},
@@ -715,58 +717,217 @@ func TestRunCommand(t *testing.T) {
 func TestUnitTests(t *testing.T) {
 	originalCachedWorkingDir := CachedWorkingDir
 	originalExecFn := ExecFn
+	originalCmdCapture := cmdCapture
+	originalBuildFS := BuildFS
+	originalJUnitFlag := JUnitFlag
 	defer func() {
 		CachedWorkingDir = originalCachedWorkingDir
 		ExecFn = originalExecFn
+		cmdCapture = originalCmdCapture
+		BuildFS = originalBuildFS
+		JUnitFlag = originalJUnitFlag
 	}()
 	CachedWorkingDir = "work"
+	BuildFS = afero.NewMemMapFs()
+	_ = BuildFS.Mkdir("work", dirMode)
+	jsonOutput := "" +
+		`{"Action":"start","Package":"p"}` + "\n" +
+		`{"Action":"run","Package":"p","Test":"TestA"}` + "\n" +
+		`{"Action":"pass","Package":"p","Test":"TestA","Elapsed":0.5}` + "\n" +
+		`{"Action":"pass","Package":"p","Elapsed":0.6}`
 	tests := map[string]struct {
+		junit         string
 		shouldSucceed bool
+		wantCmd       string
+		wantReport    bool
 		want          bool
 	}{
-		"fail":    {},
-		"succeed": {shouldSucceed: true, want: true},
+		"fail":    {wantCmd: "go test -cover ./..."},
+		"succeed": {shouldSucceed: true, wantCmd: "go test -cover ./...", want: true},
+		"bad junit file": {
+			junit:         "../junit.xml",
+			shouldSucceed: true,
+			wantCmd:       "",
+			want:          false,
+		},
+		"junit, tests fail": {
+			junit:         "junit.xml",
+			shouldSucceed: false,
+			wantCmd:       "go test -json -cover ./...",
+			wantReport:    true,
+			want:          false,
+		},
+		"junit, tests succeed": {
+			junit:         "junit.xml",
+			shouldSucceed: true,
+			wantCmd:       "go test -json -cover ./...",
+			wantReport:    true,
+			want:          true,
+		},
 	}
 	for name, tt := range tests {
 		t.Run(name, func(t *testing.T) {
+			_ = BuildFS.Remove("work/junit.xml")
 			var gotCmd string
 			ExecFn = func(_ *goyek.A, cmd string, _ ...cmd.Option) bool {
 				gotCmd = cmd
 				return tt.shouldSucceed
 			}
+			cmdCapture = func(_ *goyek.A, dC directedCommand) (bool, string, string) {
+				gotCmd = dC.command
+				return tt.shouldSucceed, jsonOutput, ""
+			}
+			junit := tt.junit
+			JUnitFlag = &junit
 			if got := UnitTests(nil); got != tt.want {
 				t.Errorf("UnitTests() = %v, want %v", got, tt.want)
 			}
-			if gotCmd != "go test -cover ./..." {
-				t.Errorf("UnitTests() = %q, want %q", gotCmd, "go test -cover ./...")
+			if gotCmd != tt.wantCmd {
+				t.Errorf("UnitTests() = %q, want %q", gotCmd, tt.wantCmd)
+			}
+			if exists, _ := afero.Exists(BuildFS, "work/junit.xml"); exists != tt.wantReport {
+				t.Errorf("UnitTests() report written = %t, want %t", exists, tt.wantReport)
 			}
 		})
 	}
 }
 
+func TestUnitTestsWithOptions(t *testing.T) {
+	originalCachedWorkingDir := CachedWorkingDir
+	originalCmdCapture := cmdCapture
+	originalBuildFS := BuildFS
+	defer func() {
+		CachedWorkingDir = originalCachedWorkingDir
+		cmdCapture = originalCmdCapture
+		BuildFS = originalBuildFS
+	}()
+	CachedWorkingDir = "work"
+	BuildFS = afero.NewMemMapFs()
+	failedRun := "" +
+		`{"Action":"fail","Package":"p","Test":"TestFlaky"}` + "\n" +
+		`{"Action":"fail","Package":"p"}`
+	passedRun := "" +
+		`{"Action":"pass","Package":"p","Test":"TestFlaky"}` + "\n" +
+		`{"Action":"pass","Package":"p"}`
+	fullProfile := "mode: set\np/p.go:1.1,2.2 4 1\np/p.go:3.1,4.2 1 0\n"
+	// what a rerun of just the failed test would write over the full profile
+	rerunProfile := "mode: set\np/p.go:1.1,2.2 4 1\n"
+	tests := map[string]struct {
+		options      TestOptions
+		stderrOnly   bool
+		wantCommands []string
+		want         bool
+	}{
+		"go command fails": {
+			options:      TestOptions{Retries: 2},
+			stderrOnly:   true,
+			wantCommands: []string{"go test -json -cover ./..."},
+			want:         false,
+		},
+		"bad coverage profile file": {
+			options:      TestOptions{CoverProfile: "../coverage.out"},
+			wantCommands: []string{},
+			want:         false,
+		},
+		"coverage too low": {
+			options:      TestOptions{Retries: 1, Coverage: &CoverageThresholds{Total: 90}},
+			wantCommands: []string{"go test -json -cover -coverprofile=coverage.out ./...", "go test -json -cover -run='^(TestFlaky)$' p"},
+			want:         false,
+		},
+		"coverage sufficient": {
+			options:      TestOptions{Retries: 1, Coverage: &CoverageThresholds{Total: 80}},
+			wantCommands: []string{"go test -json -cover -coverprofile=coverage.out ./...", "go test -json -cover -run='^(TestFlaky)$' p"},
+			want:         true,
+		},
+		"bad flaky report file": {
+			options:      TestOptions{Retries: 1, FlakyReport: "/flaky.txt"},
+			wantCommands: []string{},
+			want:         false,
+		},
+		"retry succeeds": {
+			options: TestOptions{Retries: 1, FlakyReport: "flaky.txt", JUnitReport: "junit.xml"},
+			wantCommands: []string{
+				"go test -json -cover ./...",
+				"go test -json -cover -run='^(TestFlaky)$' p",
+			},
+			want: true,
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			gotCommands := make([]string, 0)
+			cmdCapture = func(_ *goyek.A, dC directedCommand) (bool, string, string) {
+				gotCommands = append(gotCommands, dC.command)
+				if strings.Contains(dC.command, "-coverprofile=coverage.out") {
+					profile := fullProfile
+					if len(gotCommands) > 1 {
+						profile = rerunProfile
+					}
+					_ = afero.WriteFile(BuildFS, "work/coverage.out", []byte(profile), fileMode)
+				}
+				if len(gotCommands) == 1 {
+					if tt.stderrOnly {
+						return false, "", "go: no packages to test"
+					}
+					return false, failedRun, ""
+				}
+				return true, passedRun, ""
+			}
+			if got := UnitTestsWithOptions(nil, tt.options); got != tt.want {
+				t.Errorf("UnitTestsWithOptions() = %v, want %v", got, tt.want)
+			}
+			if !reflect.DeepEqual(gotCommands, tt.wantCommands) {
+				t.Errorf("UnitTestsWithOptions() commands = %v, want %v", gotCommands, tt.wantCommands)
+			}
+		})
+	}
+}
+
+// commandDir returns the directory in which the options passed to ExecFn run
+// the command
+func commandDir(options []cmd.Option) string {
+	command := &exec.Cmd{}
+	goyek.NewRunner(func(a *goyek.A) {
+		for _, option := range options {
+			option(a, command)
+		}
+	})(goyek.Input{})
+	return command.Dir
+}
+
+// inModule renames the module in a TestUpdateDependencies go.mod file after
+// the module's directory, so that each module's changes can be told apart
+func inModule(goMod, dir string) string {
+	if dir == "work" {
+		return goMod
+	}
+	return strings.Replace(goMod, "github.com/majohn-r/tools-build", "github.com/majohn-r/tools-build/"+filepath.Base(dir), 1)
+}
+
 func TestUpdateDependencies(t *testing.T) {
 	originalCachedWorkingDir := CachedWorkingDir
 	originalExecFn := ExecFn
 	originalBuildFS := BuildFS
 	originalAggressiveFlag := AggressiveFlag
+	originalDependencyReportFlag := DependencyReportFlag
 	defer func() {
 		CachedWorkingDir = originalCachedWorkingDir
 		ExecFn = originalExecFn
 		BuildFS = originalBuildFS
 		AggressiveFlag = originalAggressiveFlag
+		DependencyReportFlag = originalDependencyReportFlag
 	}()
-	BuildFS = afero.NewMemMapFs()
-	_ = BuildFS.MkdirAll(filepath.Join("work", "build"), dirMode)
-	_ = BuildFS.Mkdir("empty", dirMode)
-	_ = afero.WriteFile(BuildFS, "badDir", []byte("garbage"), fileMode)
-	_ = afero.WriteFile(BuildFS, filepath.Join("work", "go.mod"), []byte("module github.com/majohn-r/tools-build"), fileMode)
-	_ = afero.WriteFile(BuildFS, filepath.Join("work", "build", "go.mod"), []byte("module github.com/majohn-r/tools-build"), fileMode)
+	oldGoMod := "module github.com/majohn-r/tools-build\n\nrequire (\n\texample.com/a v1.0.0\n\texample.com/b v1.0.0\n)\n"
+	newGoMod := "module github.com/majohn-r/tools-build\n\nrequire (\n\texample.com/a v1.1.0\n\texample.com/c v0.1.0\n)\n"
 	tests := map[string]struct {
 		workDir              string
 		getCommandAggressive bool
 		getSucceeds          bool
 		tidySucceeds         bool
+		updatedGoMod         string
+		reportFile           string
 		wantCommands         []string
+		wantReport           string
 		want                 bool
 	}{
 		"bad dir": {
@@ -779,6 +940,12 @@ func TestUpdateDependencies(t *testing.T) {
 			wantCommands: []string{},
 			want:         true,
 		},
+		"bad report file": {
+			workDir:      "work",
+			reportFile:   "../report.md",
+			wantCommands: []string{},
+			want:         false,
+		},
 		"go get fails": {
 			workDir:      "work",
 			getSucceeds:  false,
@@ -805,17 +972,59 @@ func TestUpdateDependencies(t *testing.T) {
 			},
 			want: true,
 		},
+		"unparseable update": {
+			workDir:      "work",
+			getSucceeds:  true,
+			tidySucceeds: true,
+			updatedGoMod: "modulo github.com/majohn-r/tools-build\n",
+			wantCommands: []string{"go get -u ./...", "go mod tidy"},
+			want:         false,
+		},
+		"report written": {
+			workDir:      "work",
+			getSucceeds:  true,
+			tidySucceeds: true,
+			updatedGoMod: newGoMod,
+			reportFile:   "deps.md",
+			wantCommands: []string{
+				"go get -u ./...",
+				"go mod tidy",
+				"go get -u ./...",
+				"go mod tidy",
+			},
+			wantReport: "## Dependency updates\n\n" +
+				"| Module | Dependency | Old | New | Change |\n" +
+				"| --- | --- | --- | --- | --- |\n" +
+				"| `github.com/majohn-r/tools-build` | `example.com/a` | v1.0.0 | v1.1.0 | updated |\n" +
+				"| `github.com/majohn-r/tools-build` | `example.com/b` | v1.0.0 | - | removed |\n" +
+				"| `github.com/majohn-r/tools-build` | `example.com/c` | - | v0.1.0 | added |\n" +
+				"| `github.com/majohn-r/tools-build/build` | `example.com/a` | v1.0.0 | v1.1.0 | updated |\n" +
+				"| `github.com/majohn-r/tools-build/build` | `example.com/b` | v1.0.0 | - | removed |\n" +
+				"| `github.com/majohn-r/tools-build/build` | `example.com/c` | - | v0.1.0 | added |\n",
+			want: true,
+		},
 	}
 	for name, tt := range tests {
 		t.Run(name, func(t *testing.T) {
+			BuildFS = afero.NewMemMapFs()
+			_ = BuildFS.MkdirAll(filepath.Join("work", "build"), dirMode)
+			_ = BuildFS.Mkdir("empty", dirMode)
+			_ = afero.WriteFile(BuildFS, "badDir", []byte("garbage"), fileMode)
+			for _, dir := range []string{"work", filepath.Join("work", "build")} {
+				_ = afero.WriteFile(BuildFS, filepath.Join(dir, "go.mod"), []byte(inModule(oldGoMod, dir)), fileMode)
+			}
 			gotCommands := make([]string, 0)
 			CachedWorkingDir = tt.workDir
-			ExecFn = func(_ *goyek.A, cmd string, _ ...cmd.Option) bool {
+			ExecFn = func(_ *goyek.A, cmd string, options ...cmd.Option) bool {
 				gotCommands = append(gotCommands, cmd)
 				if strings.HasPrefix(cmd, "go get") {
 					return tt.getSucceeds
 				}
 				if strings.HasPrefix(cmd, "go mod") {
+					if tt.updatedGoMod != "" {
+						dir := commandDir(options)
+						_ = afero.WriteFile(BuildFS, filepath.Join(dir, "go.mod"), []byte(inModule(tt.updatedGoMod, dir)), fileMode)
+					}
 					return tt.tidySucceeds
 				}
 				t.Errorf("UpdateDependencies() sent unexpected command: %q", cmd)
@@ -823,12 +1032,153 @@ func TestUpdateDependencies(t *testing.T) {
 			}
 			a := tt.getCommandAggressive
 			AggressiveFlag = &a
+			reportFile := tt.reportFile
+			DependencyReportFlag = &reportFile
 			if got := UpdateDependencies(nil); got != tt.want {
 				t.Errorf("UpdateDependencies() = %v, want %v", got, tt.want)
 			}
 			if !reflect.DeepEqual(gotCommands, tt.wantCommands) {
 				t.Errorf("UpdateDependencies() commands = %v, want %v", gotCommands, tt.wantCommands)
 			}
+			if tt.wantReport != "" {
+				content, _ := afero.ReadFile(BuildFS, filepath.Join("work", tt.reportFile))
+				if string(content) != tt.wantReport {
+					t.Errorf("UpdateDependencies() report = %q, want %q", content, tt.wantReport)
+				}
+			}
+		})
+	}
+}
+
+func TestUpdateDependenciesWithPolicy(t *testing.T) {
+	originalCachedWorkingDir := CachedWorkingDir
+	originalExecFn := ExecFn
+	originalBuildFS := BuildFS
+	defer func() {
+		CachedWorkingDir = originalCachedWorkingDir
+		ExecFn = originalExecFn
+		BuildFS = originalBuildFS
+	}()
+	CachedWorkingDir = "work"
+	oldGoMod := "module example.com/m\n\nrequire (\n\texample.com/a v1.0.0\n\texample.com/b v1.0.0 // indirect\n)\n"
+	newGoMod := "module example.com/m\n\nrequire (\n\texample.com/a v1.1.0\n\texample.com/b v1.0.0 // indirect\n)\n"
+	// what go get writes when the new version of example.com/a requires a
+	// newer example.com/b, and example.com/b is not held
+	pulledGoMod := "module example.com/m\n\nrequire (\n\texample.com/a v1.1.0\n\texample.com/b v1.2.0 // indirect\n)\n"
+	tests := map[string]struct {
+		policy       UpdatePolicy
+		getSucceeds  bool
+		updatedGoMod string
+		pullsB       bool
+		failCommand  string
+		wantCommands []string
+		wantGoMod    string
+		want         bool
+	}{
+		"bad pin": {
+			policy:       UpdatePolicy{Pinned: map[string]string{"example.com/a": "latest"}},
+			wantCommands: []string{},
+			wantGoMod:    oldGoMod,
+			want:         false,
+		},
+		"nothing selected": {
+			policy:       UpdatePolicy{Deny: []string{"example.com/..."}, Verify: true},
+			wantCommands: []string{"go mod tidy"},
+			wantGoMod:    oldGoMod,
+			want:         true,
+		},
+		"get fails": {
+			policy:       UpdatePolicy{DirectOnly: true, PatchOnly: true},
+			getSucceeds:  false,
+			wantCommands: []string{"go get example.com/a@patch"},
+			wantGoMod:    oldGoMod,
+			want:         false,
+		},
+		"pinned": {
+			policy:      UpdatePolicy{DirectOnly: true, Pinned: map[string]string{"example.com/b": "v0.9.0"}},
+			getSucceeds: true,
+			wantCommands: []string{
+				"go get example.com/a@latest example.com/b@v0.9.0",
+				"go mod tidy",
+			},
+			wantGoMod: oldGoMod,
+			want:      true,
+		},
+		"denied dependency held": {
+			policy:       UpdatePolicy{Deny: []string{"example.com/b"}},
+			getSucceeds:  true,
+			updatedGoMod: newGoMod,
+			pullsB:       true,
+			wantCommands: []string{"go get example.com/a@latest example.com/b@v1.0.0", "go mod tidy"},
+			wantGoMod:    newGoMod,
+			want:         true,
+		},
+		"pinned dependency held": {
+			policy:       UpdatePolicy{Pinned: map[string]string{"example.com/b": "v1.0.0"}},
+			getSucceeds:  true,
+			updatedGoMod: newGoMod,
+			pullsB:       true,
+			wantCommands: []string{"go get example.com/a@latest example.com/b@v1.0.0", "go mod tidy"},
+			wantGoMod:    newGoMod,
+			want:         true,
+		},
+		"verified": {
+			policy:       UpdatePolicy{Verify: true},
+			getSucceeds:  true,
+			updatedGoMod: newGoMod,
+			wantCommands: []string{"go get -u ./...", "go mod tidy", "go build ./...", "go test ./..."},
+			wantGoMod:    newGoMod,
+			want:         true,
+		},
+		"build fails": {
+			policy:       UpdatePolicy{Verify: true},
+			getSucceeds:  true,
+			updatedGoMod: newGoMod,
+			failCommand:  "go build ./...",
+			wantCommands: []string{"go get -u ./...", "go mod tidy", "go build ./..."},
+			wantGoMod:    oldGoMod,
+			want:         false,
+		},
+		"tests fail": {
+			policy:       UpdatePolicy{Verify: true},
+			getSucceeds:  true,
+			updatedGoMod: newGoMod,
+			failCommand:  "go test ./...",
+			wantCommands: []string{"go get -u ./...", "go mod tidy", "go build ./...", "go test ./..."},
+			wantGoMod:    oldGoMod,
+			want:         false,
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			BuildFS = afero.NewMemMapFs()
+			_ = BuildFS.MkdirAll("work", dirMode)
+			_ = afero.WriteFile(BuildFS, filepath.Join("work", "go.mod"), []byte(oldGoMod), fileMode)
+			gotCommands := make([]string, 0)
+			ExecFn = func(_ *goyek.A, cmd string, _ ...cmd.Option) bool {
+				gotCommands = append(gotCommands, cmd)
+				if strings.HasPrefix(cmd, "go get") {
+					updatedGoMod := tt.updatedGoMod
+					if tt.pullsB && !strings.Contains(cmd, "example.com/b@") {
+						updatedGoMod = pulledGoMod
+					}
+					if updatedGoMod != "" {
+						_ = afero.WriteFile(BuildFS, filepath.Join("work", "go.mod"), []byte(updatedGoMod), fileMode)
+					}
+					return tt.getSucceeds
+				}
+				return cmd != tt.failCommand
+			}
+			if got := UpdateDependenciesWithPolicy(nil, tt.policy); got != tt.want {
+				t.Errorf("UpdateDependenciesWithPolicy() = %v, want %v", got, tt.want)
+			}
+			if !reflect.DeepEqual(gotCommands, tt.wantCommands) {
+				t.Errorf("UpdateDependenciesWithPolicy() commands = %v, want %v", gotCommands, tt.wantCommands)
+			}
+			goMod, _ := afero.ReadFile(BuildFS, filepath.Join("work", "go.mod"))
+			if string(goMod) != tt.wantGoMod {
+				t.Errorf("UpdateDependenciesWithPolicy() go.mod = %q, want %q", goMod, tt.wantGoMod)
+			}
 		})
 	}
 }
@@ -836,18 +1186,33 @@ func TestUpdateDependencies(t *testing.T) {
 func TestVulnerabilityCheck(t *testing.T) {
 	originalCachedWorkingDir := CachedWorkingDir
 	originalExecFn := ExecFn
+	originalCmdCapture := cmdCapture
+	originalBuildFS := BuildFS
+	originalNowFn := NowFn
+	originalVulnerabilitySuppressionsFlag := VulnerabilitySuppressionsFlag
 	defer func() {
 		CachedWorkingDir = originalCachedWorkingDir
 		ExecFn = originalExecFn
+		cmdCapture = originalCmdCapture
+		BuildFS = originalBuildFS
+		NowFn = originalNowFn
+		VulnerabilitySuppressionsFlag = originalVulnerabilitySuppressionsFlag
 	}()
-	// not used, and keeps WorkingDir() from getting exercised
 	CachedWorkingDir = "work"
+	NowFn = func() time.Time { return time.Date(2026, 6, 15, 12, 0, 0, 0, time.UTC) }
 	tests := map[string]struct {
+		suppressions               string
 		installSucceeds            bool
 		vulnerabilityCheckSucceeds bool
+		output                     string
 		wantCommands               []string
 		want                       bool
 	}{
+		"bad suppression file": {
+			suppressions: "[{\"id\": \"GO-2024-0001\"}]",
+			wantCommands: []string{},
+			want:         false,
+		},
 		"install fails": {
 			installSucceeds: false,
 			wantCommands: []string{
@@ -860,34 +1225,75 @@ func TestVulnerabilityCheck(t *testing.T) {
 			vulnerabilityCheckSucceeds: false,
 			wantCommands: []string{
 				"go install -v golang.org/x/vuln/cmd/govulncheck@latest",
-				"govulncheck -show verbose ./...",
+				"govulncheck -format json ./...",
+			},
+			want: false,
+		},
+		"vulnerability called": {
+			installSucceeds:            true,
+			vulnerabilityCheckSucceeds: true,
+			output:                     govulncheckOutput,
+			wantCommands: []string{
+				"go install -v golang.org/x/vuln/cmd/govulncheck@latest",
+				"govulncheck -format json ./...",
+			},
+			want: false,
+		},
+		"vulnerability suppressed": {
+			suppressions:               `[{"id": "GO-2024-0001", "justification": "not reachable", "expires": "2026-06-15"}]`,
+			installSucceeds:            true,
+			vulnerabilityCheckSucceeds: true,
+			output:                     govulncheckOutput,
+			wantCommands: []string{
+				"go install -v golang.org/x/vuln/cmd/govulncheck@latest",
+				"govulncheck -format json ./...",
+			},
+			want: true,
+		},
+		"suppression expired": {
+			suppressions:               `[{"id": "GO-2024-0001", "justification": "not reachable", "expires": "2026-06-14"}]`,
+			installSucceeds:            true,
+			vulnerabilityCheckSucceeds: true,
+			output:                     govulncheckOutput,
+			wantCommands: []string{
+				"go install -v golang.org/x/vuln/cmd/govulncheck@latest",
+				"govulncheck -format json ./...",
 			},
 			want: false,
 		},
 		"vulnerability check succeeds": {
 			installSucceeds:            true,
 			vulnerabilityCheckSucceeds: true,
+			output:                     `{"config": {"scan_level": "symbol"}}`,
 			wantCommands: []string{
 				"go install -v golang.org/x/vuln/cmd/govulncheck@latest",
-				"govulncheck -show verbose ./...",
+				"govulncheck -format json ./...",
 			},
 			want: true,
 		},
 	}
 	for name, tt := range tests {
 		t.Run(name, func(t *testing.T) {
+			BuildFS = afero.NewMemMapFs()
+			_ = BuildFS.MkdirAll("work", dirMode)
+			if tt.suppressions != "" {
+				_ = afero.WriteFile(BuildFS, "work/suppressions.json", []byte(tt.suppressions), fileMode)
+			}
+			suppressionFile := "suppressions.json"
+			VulnerabilitySuppressionsFlag = &suppressionFile
 			gotCommands := make([]string, 0)
 			ExecFn = func(_ *goyek.A, cmd string, _ ...cmd.Option) bool {
 				gotCommands = append(gotCommands, cmd)
 				if strings.Contains(cmd, " install ") {
 					return tt.installSucceeds
 				}
-				if strings.HasPrefix(cmd, "govulncheck ") {
-					return tt.vulnerabilityCheckSucceeds
-				}
 				t.Errorf("VulnerabilityCheck() sent unexpected command: %q", cmd)
 				return false
 			}
+			cmdCapture = func(_ *goyek.A, dC directedCommand) (bool, string, string) {
+				gotCommands = append(gotCommands, dC.command)
+				return tt.vulnerabilityCheckSucceeds, tt.output, ""
+			}
 			if got := VulnerabilityCheck(nil); got != tt.want {
 				t.Errorf("VulnerabilityCheck() = %v, want %v", got, tt.want)
 			}
@@ -1107,3 +1513,92 @@ func Test_commandOutput(t *testing.T) {
 		})
 	}
 }
+
+func Test_captureCommand(t *testing.T) {
+	originalExecFn := ExecFn
+	defer func() {
+		ExecFn = originalExecFn
+	}()
+	tests := map[string]struct {
+		dC           directedCommand
+		execSucceeds bool
+		execRan      bool
+		wantState    bool
+	}{
+		"success": {
+			dC:           directedCommand{command: "go test -json ./...", dir: "work"},
+			execSucceeds: true,
+			execRan:      true,
+			wantState:    true,
+		},
+		"fail": {
+			dC:           directedCommand{command: "go test -json ./...", dir: "work"},
+			execSucceeds: false,
+			execRan:      true,
+			wantState:    false,
+		},
+		"bad set up": {
+			dC: directedCommand{
+				command: "go test -json ./...",
+				dir:     "work",
+				envVars: []EnvVarMemento{
+					{Name: "HOME", Value: "/home"},
+					{Name: "HOME", Value: "/home"},
+				},
+			},
+			execSucceeds: true,
+			execRan:      false,
+			wantState:    false,
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			var ran bool
+			ExecFn = func(_ *goyek.A, cmd string, _ ...cmd.Option) bool {
+				ran = true
+				return tt.execSucceeds
+			}
+			gotState, gotStdout, gotStderr := captureCommand(nil, tt.dC)
+			if gotState != tt.wantState {
+				t.Errorf("captureCommand() gotState = %v, want %v", gotState, tt.wantState)
+			}
+			if gotStdout != "" || gotStderr != "" {
+				t.Errorf("captureCommand() got output %q, %q", gotStdout, gotStderr)
+			}
+			if ran != tt.execRan {
+				t.Errorf("captureCommand() ran = %v, want %v", ran, tt.execRan)
+			}
+		})
+	}
+}
+
+func Test_quoteArg(t *testing.T) {
+	tests := map[string]struct {
+		s    string
+		want string
+	}{
+		"empty": {
+			s:    "",
+			want: "''",
+		},
+		"plain": {
+			s:    "./pkg/...",
+			want: "./pkg/...",
+		},
+		"regular expression": {
+			s:    "^(TestA|TestB)$",
+			want: "'^(TestA|TestB)$'",
+		},
+		"embedded quote": {
+			s:    "it's",
+			want: `'it'"'"'s'`,
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			if got := quoteArg(tt.s); got != tt.want {
+				t.Errorf("quoteArg() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
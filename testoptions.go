@@ -0,0 +1,237 @@
+package tools_build
+
+import (
+	"flag"
+	"fmt"
+	"strings"
+)
+
+// Copyright © 2026 Marc Johnson (marc.johnson27591@gmail.com)
+
+var (
+	// MinCoverageFlag is a flag that sets the minimum total statement coverage percentage required of the unit tests
+	MinCoverageFlag = flag.Float64(
+		"mincoverage",
+		0,
+		"set to the minimum total statement coverage percentage required of the unit tests")
+	// MinPackageCoverageFlag is a flag that sets the minimum statement coverage percentage required of each package
+	MinPackageCoverageFlag = flag.Float64(
+		"minpackagecoverage",
+		0,
+		"set to the minimum statement coverage percentage required of each package")
+	// FlakyReportFlag is a flag that names a file to which the report of flaky unit tests is written
+	FlakyReportFlag = flag.String(
+		"flakyreport",
+		"",
+		"set to the name of a file to which the report of flaky unit tests will be written")
+	// TestCoverProfileFlag is a flag that names the file to which the unit tests write their coverage profile
+	TestCoverProfileFlag = flag.String(
+		"testcoverprofile",
+		"",
+		"set to the name of a file to which the unit tests will write their coverage profile")
+	// TestCountFlag is a flag that sets the number of times each unit test is run
+	TestCountFlag = flag.Int(
+		"testcount",
+		0,
+		"set to run each unit test the specified number of times")
+	// TestFailFastFlag is a flag that stops the unit tests after the first failure
+	TestFailFastFlag = flag.Bool(
+		"testfailfast",
+		false,
+		"set to stop running unit tests after the first failure")
+	// TestPackagesFlag is a flag that restricts the unit tests to a comma-delimited set of packages
+	TestPackagesFlag = flag.String(
+		"testpackages",
+		"",
+		"set to a comma-delimited set of packages to test (default ./...)")
+	// TestRaceFlag is a flag that enables the race detector when running unit tests
+	TestRaceFlag = flag.Bool(
+		"testrace",
+		false,
+		"set to enable the race detector when running unit tests")
+	// TestRetriesFlag is a flag that sets the number of times failed unit tests are rerun
+	TestRetriesFlag = flag.Int(
+		"testretries",
+		0,
+		"set to rerun failed unit tests up to the specified number of times")
+	// TestRunFlag is a flag that restricts the unit tests to those matching a regular expression
+	TestRunFlag = flag.String(
+		"testrun",
+		"",
+		"set to a regular expression selecting the unit tests to run")
+	// TestShortFlag is a flag that tells long-running unit tests to shorten their run time
+	TestShortFlag = flag.Bool(
+		"testshort",
+		false,
+		"set to tell long-running unit tests to shorten their run time")
+	// TestShuffleFlag is a flag that randomizes the execution order of unit tests
+	TestShuffleFlag = flag.String(
+		"testshuffle",
+		"",
+		"set to 'on', 'off', or a seed value to control randomized unit test execution order")
+	// TestSkipFlag is a flag that skips unit tests matching a regular expression
+	TestSkipFlag = flag.String(
+		"testskip",
+		"",
+		"set to a regular expression selecting unit tests to skip")
+	// TestTagsFlag is a flag that sets a comma-delimited set of build tags for the unit tests
+	TestTagsFlag = flag.String(
+		"testtags",
+		"",
+		"set to a comma-delimited set of build tags to use when running unit tests")
+	// TestTimeoutFlag is a flag that sets the maximum duration of each unit test binary
+	TestTimeoutFlag = flag.String(
+		"testtimeout",
+		"",
+		"set to a duration, such as 10m, after which a unit test binary is stopped")
+)
+
+// TestOptions controls how the unit tests are run; the zero value runs all
+// the tests in the working directory's module, with coverage enabled
+type TestOptions struct {
+	// Race enables the race detector
+	Race bool
+	// Shuffle is the -shuffle value: 'on', 'off', or a seed; empty leaves the
+	// go test default
+	Shuffle string
+	// Count is the number of times to run each test; zero leaves the go test
+	// default
+	Count int
+	// Timeout is the maximum duration of each test binary, such as "10m";
+	// empty leaves the go test default
+	Timeout string
+	// Tags are the build tags to apply
+	Tags []string
+	// Run is a regular expression selecting the tests to run
+	Run string
+	// Skip is a regular expression selecting the tests to skip
+	Skip string
+	// Short tells long-running tests to shorten their run time
+	Short bool
+	// FailFast stops the run after the first test failure
+	FailFast bool
+	// Packages are the packages to test; if empty, "./..." is tested
+	Packages []string
+	// JUnitReport, if not empty, names the file to which a JUnit XML report is
+	// written
+	JUnitReport string
+	// Retries is the number of times failed tests are rerun; if the failed
+	// tests eventually pass, the run succeeds, and the tests are reported as
+	// flaky
+	Retries int
+	// FlakyReport, if not empty, names the file to which the flaky test report
+	// is written
+	FlakyReport string
+	// CoverProfile, if not empty, names the file to which the coverage profile
+	// is written
+	CoverProfile string
+	// Coverage, if not nil, is enforced against the coverage profile (see
+	// CoverageGate) after the tests pass; if CoverProfile is empty,
+	// DefaultCoverProfile is used
+	Coverage *CoverageThresholds
+}
+
+// DefaultCoverProfile is the coverage profile written when coverage thresholds
+// are enforced and no profile is named
+const DefaultCoverProfile = "coverage.out"
+
+// FlagTestOptions returns the TestOptions specified by the command line flags
+func FlagTestOptions() TestOptions {
+	return TestOptions{
+		Race:         *TestRaceFlag,
+		Shuffle:      *TestShuffleFlag,
+		Count:        *TestCountFlag,
+		Timeout:      *TestTimeoutFlag,
+		Tags:         splitList(*TestTagsFlag),
+		Run:          *TestRunFlag,
+		Skip:         *TestSkipFlag,
+		Short:        *TestShortFlag,
+		FailFast:     *TestFailFastFlag,
+		Packages:     splitList(*TestPackagesFlag),
+		JUnitReport:  *JUnitFlag,
+		Retries:      *TestRetriesFlag,
+		FlakyReport:  *FlakyReportFlag,
+		CoverProfile: *TestCoverProfileFlag,
+		Coverage:     flagCoverageThresholds(),
+	}
+}
+
+func flagCoverageThresholds() *CoverageThresholds {
+	if *MinCoverageFlag <= 0 && *MinPackageCoverageFlag <= 0 {
+		return nil
+	}
+	return &CoverageThresholds{Total: *MinCoverageFlag, Package: *MinPackageCoverageFlag}
+}
+
+// command assembles the go test command line described by the options; if
+// jsonOutput is true, the command produces 'go test -json' output
+func (o TestOptions) command(jsonOutput bool) string {
+	cmdParts := []string{"go", "test"}
+	if jsonOutput {
+		cmdParts = append(cmdParts, "-json")
+	}
+	cmdParts = append(cmdParts, "-cover")
+	if profile := o.coverProfile(); profile != "" {
+		cmdParts = append(cmdParts, "-coverprofile="+quoteArg(profile))
+	}
+	if o.Race {
+		cmdParts = append(cmdParts, "-race")
+	}
+	if o.Shuffle != "" {
+		cmdParts = append(cmdParts, "-shuffle="+quoteArg(o.Shuffle))
+	}
+	if o.Count > 0 {
+		cmdParts = append(cmdParts, fmt.Sprintf("-count=%d", o.Count))
+	}
+	if o.Timeout != "" {
+		cmdParts = append(cmdParts, "-timeout="+quoteArg(o.Timeout))
+	}
+	if len(o.Tags) != 0 {
+		cmdParts = append(cmdParts, "-tags="+quoteArg(strings.Join(o.Tags, ",")))
+	}
+	if o.Run != "" {
+		cmdParts = append(cmdParts, "-run="+quoteArg(o.Run))
+	}
+	if o.Skip != "" {
+		cmdParts = append(cmdParts, "-skip="+quoteArg(o.Skip))
+	}
+	if o.Short {
+		cmdParts = append(cmdParts, "-short")
+	}
+	if o.FailFast {
+		cmdParts = append(cmdParts, "-failfast")
+	}
+	cmdParts = append(cmdParts, o.packages()...)
+	return strings.Join(cmdParts, " ")
+}
+
+// coverProfile returns the name of the coverage profile to be written, if any
+func (o TestOptions) coverProfile() string {
+	if o.CoverProfile == "" && o.Coverage != nil {
+		return DefaultCoverProfile
+	}
+	return o.CoverProfile
+}
+
+func (o TestOptions) packages() []string {
+	if len(o.Packages) == 0 {
+		return []string{"./..."}
+	}
+	packages := make([]string, 0, len(o.Packages))
+	for _, pkg := range o.Packages {
+		packages = append(packages, quoteArg(pkg))
+	}
+	return packages
+}
+
+// splitList splits a comma-delimited flag value into its trimmed, non-empty
+// elements
+func splitList(s string) []string {
+	var values []string
+	for value := range strings.SplitSeq(s, ",") {
+		if trimmed := strings.TrimSpace(value); trimmed != "" {
+			values = append(values, trimmed)
+		}
+	}
+	return values
+}
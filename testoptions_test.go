@@ -0,0 +1,178 @@
+package tools_build
+
+import (
+	"reflect"
+	"testing"
+)
+
+// Copyright © 2026 Marc Johnson (marc.johnson27591@gmail.com)
+
+func TestFlagTestOptions(t *testing.T) {
+	originalTestCountFlag := TestCountFlag
+	originalTestFailFastFlag := TestFailFastFlag
+	originalTestPackagesFlag := TestPackagesFlag
+	originalTestRaceFlag := TestRaceFlag
+	originalTestRunFlag := TestRunFlag
+	originalTestShortFlag := TestShortFlag
+	originalTestShuffleFlag := TestShuffleFlag
+	originalTestSkipFlag := TestSkipFlag
+	originalTestTagsFlag := TestTagsFlag
+	originalTestTimeoutFlag := TestTimeoutFlag
+	originalJUnitFlag := JUnitFlag
+	defer func() {
+		TestCountFlag = originalTestCountFlag
+		TestFailFastFlag = originalTestFailFastFlag
+		TestPackagesFlag = originalTestPackagesFlag
+		TestRaceFlag = originalTestRaceFlag
+		TestRunFlag = originalTestRunFlag
+		TestShortFlag = originalTestShortFlag
+		TestShuffleFlag = originalTestShuffleFlag
+		TestSkipFlag = originalTestSkipFlag
+		TestTagsFlag = originalTestTagsFlag
+		TestTimeoutFlag = originalTestTimeoutFlag
+		JUnitFlag = originalJUnitFlag
+	}()
+	count := 3
+	TestCountFlag = &count
+	failFast := true
+	TestFailFastFlag = &failFast
+	packages := "./a/..., ./b"
+	TestPackagesFlag = &packages
+	race := true
+	TestRaceFlag = &race
+	run := "^TestA$"
+	TestRunFlag = &run
+	short := true
+	TestShortFlag = &short
+	shuffle := "on"
+	TestShuffleFlag = &shuffle
+	skip := "^TestB$"
+	TestSkipFlag = &skip
+	tags := "integration,linux"
+	TestTagsFlag = &tags
+	timeout := "5m"
+	TestTimeoutFlag = &timeout
+	junit := "junit.xml"
+	JUnitFlag = &junit
+	want := TestOptions{
+		Race:        true,
+		Shuffle:     "on",
+		Count:       3,
+		Timeout:     "5m",
+		Tags:        []string{"integration", "linux"},
+		Run:         "^TestA$",
+		Skip:        "^TestB$",
+		Short:       true,
+		FailFast:    true,
+		Packages:    []string{"./a/...", "./b"},
+		JUnitReport: "junit.xml",
+	}
+	if got := FlagTestOptions(); !reflect.DeepEqual(got, want) {
+		t.Errorf("FlagTestOptions() = %+v, want %+v", got, want)
+	}
+}
+
+func TestTestOptions_command(t *testing.T) {
+	tests := map[string]struct {
+		options    TestOptions
+		jsonOutput bool
+		want       string
+	}{
+		"defaults": {
+			options: TestOptions{},
+			want:    "go test -cover ./...",
+		},
+		"json": {
+			options:    TestOptions{},
+			jsonOutput: true,
+			want:       "go test -json -cover ./...",
+		},
+		"quick local run": {
+			options: TestOptions{Short: true, FailFast: true, Packages: []string{"./internal/..."}},
+			want:    "go test -cover -short -failfast ./internal/...",
+		},
+		"nightly race run": {
+			options: TestOptions{Race: true, Shuffle: "on", Count: 10, Timeout: "30m", Tags: []string{"integration"}},
+			want:    "go test -cover -race -shuffle=on -count=10 -timeout=30m -tags=integration ./...",
+		},
+		"coverage profile": {
+			options: TestOptions{CoverProfile: "cover.out"},
+			want:    "go test -cover -coverprofile=cover.out ./...",
+		},
+		"coverage thresholds": {
+			options: TestOptions{Coverage: &CoverageThresholds{Total: 80}},
+			want:    "go test -cover -coverprofile=coverage.out ./...",
+		},
+		"filters": {
+			options: TestOptions{Run: "^TestA$|^TestB$", Skip: "Slow"},
+			want:    "go test -cover -run='^TestA$|^TestB$' -skip=Slow ./...",
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			if got := tt.options.command(tt.jsonOutput); got != tt.want {
+				t.Errorf("TestOptions.command() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_flagCoverageThresholds(t *testing.T) {
+	originalMinCoverageFlag := MinCoverageFlag
+	originalMinPackageCoverageFlag := MinPackageCoverageFlag
+	defer func() {
+		MinCoverageFlag = originalMinCoverageFlag
+		MinPackageCoverageFlag = originalMinPackageCoverageFlag
+	}()
+	tests := map[string]struct {
+		minCoverage        float64
+		minPackageCoverage float64
+		want               *CoverageThresholds
+	}{
+		"unset": {
+			want: nil,
+		},
+		"total": {
+			minCoverage: 75,
+			want:        &CoverageThresholds{Total: 75},
+		},
+		"package": {
+			minPackageCoverage: 60,
+			want:               &CoverageThresholds{Package: 60},
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			minCoverage := tt.minCoverage
+			MinCoverageFlag = &minCoverage
+			minPackageCoverage := tt.minPackageCoverage
+			MinPackageCoverageFlag = &minPackageCoverage
+			if got := flagCoverageThresholds(); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("flagCoverageThresholds() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_splitList(t *testing.T) {
+	tests := map[string]struct {
+		s    string
+		want []string
+	}{
+		"empty": {
+			s:    "",
+			want: nil,
+		},
+		"messy": {
+			s:    " a, ,b ,c,",
+			want: []string{"a", "b", "c"},
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			if got := splitList(tt.s); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("splitList() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
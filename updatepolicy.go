@@ -0,0 +1,162 @@
+package tools_build
+
+import (
+	"flag"
+	"fmt"
+	"maps"
+	"os"
+	"slices"
+	"strings"
+
+	"golang.org/x/mod/semver"
+)
+
+// Copyright © 2026 Marc Johnson (marc.johnson27591@gmail.com)
+
+var (
+	// UpdateAllowFlag is a flag that restricts dependency updates to a comma-delimited set of module path patterns
+	UpdateAllowFlag = flag.String(
+		"updateallow",
+		"",
+		"set to a comma-delimited set of module path patterns; only matching dependencies will be updated")
+	// UpdateDenyFlag is a flag that excludes a comma-delimited set of module path patterns from dependency updates
+	UpdateDenyFlag = flag.String(
+		"updatedeny",
+		"",
+		"set to a comma-delimited set of module path patterns; matching dependencies will not be updated")
+	// UpdateDirectOnlyFlag is a flag that restricts dependency updates to direct dependencies
+	UpdateDirectOnlyFlag = flag.Bool(
+		"updatedirectonly",
+		false,
+		"set to update only direct dependencies")
+	// UpdatePatchOnlyFlag is a flag that restricts dependency updates to patch releases
+	UpdatePatchOnlyFlag = flag.Bool(
+		"updatepatchonly",
+		false,
+		"set to update dependencies to newer patch releases only")
+	// UpdatePinFlag is a flag that holds a comma-delimited set of modules at specific versions
+	UpdatePinFlag = flag.String(
+		"updatepin",
+		"",
+		"set to a comma-delimited set of path@version values; those modules will be held at those versions")
+	// UpdateVerifyFlag is a flag that verifies each module after its dependencies are updated
+	UpdateVerifyFlag = flag.Bool(
+		"updateverify",
+		false,
+		"set to build and test each module after updating its dependencies, reverting the update on failure")
+)
+
+// UpdatePolicy controls which dependencies UpdateDependenciesWithPolicy
+// updates, and how far; the zero value updates every dependency to its latest
+// minor or patch release. Module path patterns are either module paths or
+// module path prefixes followed by "/...", such as "golang.org/x/..."
+type UpdatePolicy struct {
+	// Aggressive bypasses the module proxy (GOPROXY=direct) when getting
+	// updates
+	Aggressive bool
+	// PatchOnly restricts updates to newer patch releases
+	PatchOnly bool
+	// DirectOnly restricts updates to direct dependencies
+	DirectOnly bool
+	// Allow, if not empty, restricts updates to dependencies matching any of
+	// its module path patterns
+	Allow []string
+	// Deny holds dependencies matching any of its module path patterns at
+	// their current versions, even when updating other dependencies would
+	// otherwise upgrade them
+	Deny []string
+	// Pinned holds the specified modules, keyed by module path, at the
+	// specified versions, even when updating other dependencies would
+	// otherwise upgrade them; pinned modules that a module does not require
+	// are ignored
+	Pinned map[string]string
+	// Verify builds and tests each module after updating its dependencies;
+	// if either fails, the module's go.mod and go.sum files are restored
+	Verify bool
+}
+
+// FlagUpdatePolicy returns the UpdatePolicy specified by the command line
+// flags
+func FlagUpdatePolicy() UpdatePolicy {
+	policy := UpdatePolicy{
+		Aggressive: *AggressiveFlag,
+		PatchOnly:  *UpdatePatchOnlyFlag,
+		DirectOnly: *UpdateDirectOnlyFlag,
+		Allow:      splitList(*UpdateAllowFlag),
+		Deny:       splitList(*UpdateDenyFlag),
+		Verify:     *UpdateVerifyFlag,
+	}
+	for _, pin := range splitList(*UpdatePinFlag) {
+		if policy.Pinned == nil {
+			policy.Pinned = map[string]string{}
+		}
+		path, version, _ := strings.Cut(pin, "@")
+		policy.Pinned[path] = version
+	}
+	return policy
+}
+
+// validate reports pinned modules that lack a path or a valid version
+func (up UpdatePolicy) validate() bool {
+	valid := true
+	for _, path := range slices.Sorted(maps.Keys(up.Pinned)) {
+		if path == "" || !semver.IsValid(up.Pinned[path]) {
+			fmt.Fprintf(os.Stderr, "cannot accept %q as a valid pinned module version\n", path+"@"+up.Pinned[path])
+			valid = false
+		}
+	}
+	return valid
+}
+
+// selective returns true if the policy requires choosing, module by module,
+// which dependencies to update
+func (up UpdatePolicy) selective() bool {
+	return up.DirectOnly || len(up.Allow) != 0 || len(up.Deny) != 0 || len(up.Pinned) != 0
+}
+
+// updateCommands returns the 'go get' commands that apply the policy to the
+// module whose go.mod file is recorded in the snapshot. Denied and pinned
+// dependencies are named, at their current or pinned versions, in the same
+// command as the updates, so that the updates cannot upgrade them
+func (up UpdatePolicy) updateCommands(snapshot moduleSnapshot) ([]string, bool) {
+	if !up.selective() {
+		if up.PatchOnly {
+			return []string{"go get -u=patch ./..."}, true
+		}
+		return []string{"go get -u ./..."}, true
+	}
+	file, ok := snapshot.parse()
+	if !ok {
+		return nil, false
+	}
+	query := "latest"
+	if up.PatchOnly {
+		query = "patch"
+	}
+	updates := make([]string, 0)
+	holds := make([]string, 0)
+	repinned := false
+	for _, requirement := range file.Require {
+		path := requirement.Mod.Path
+		if version, pinned := up.Pinned[path]; pinned {
+			holds = append(holds, path+"@"+version)
+			repinned = repinned || version != requirement.Mod.Version
+			continue
+		}
+		if matchesAnyPackagePattern(path, up.Deny) {
+			holds = append(holds, path+"@"+requirement.Mod.Version)
+			continue
+		}
+		if up.DirectOnly && requirement.Indirect {
+			continue
+		}
+		if len(up.Allow) != 0 && !matchesAnyPackagePattern(path, up.Allow) {
+			continue
+		}
+		updates = append(updates, path+"@"+query)
+	}
+	if len(updates) == 0 && !repinned {
+		return []string{}, true
+	}
+	return []string{"go get " + strings.Join(append(updates, holds...), " ")}, true
+}
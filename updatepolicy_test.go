@@ -0,0 +1,201 @@
+package tools_build
+
+import (
+	"reflect"
+	"testing"
+)
+
+// Copyright © 2026 Marc Johnson (marc.johnson27591@gmail.com)
+
+func TestFlagUpdatePolicy(t *testing.T) {
+	originalAggressiveFlag := AggressiveFlag
+	originalUpdateAllowFlag := UpdateAllowFlag
+	originalUpdateDenyFlag := UpdateDenyFlag
+	originalUpdateDirectOnlyFlag := UpdateDirectOnlyFlag
+	originalUpdatePatchOnlyFlag := UpdatePatchOnlyFlag
+	originalUpdatePinFlag := UpdatePinFlag
+	originalUpdateVerifyFlag := UpdateVerifyFlag
+	defer func() {
+		AggressiveFlag = originalAggressiveFlag
+		UpdateAllowFlag = originalUpdateAllowFlag
+		UpdateDenyFlag = originalUpdateDenyFlag
+		UpdateDirectOnlyFlag = originalUpdateDirectOnlyFlag
+		UpdatePatchOnlyFlag = originalUpdatePatchOnlyFlag
+		UpdatePinFlag = originalUpdatePinFlag
+		UpdateVerifyFlag = originalUpdateVerifyFlag
+	}()
+	tests := map[string]struct {
+		aggressive bool
+		allow      string
+		deny       string
+		directOnly bool
+		patchOnly  bool
+		pin        string
+		verify     bool
+		want       UpdatePolicy
+	}{
+		"defaults": {
+			want: UpdatePolicy{},
+		},
+		"everything": {
+			aggressive: true,
+			allow:      "golang.org/x/..., example.com/a",
+			deny:       "example.com/b",
+			directOnly: true,
+			patchOnly:  true,
+			pin:        "example.com/c@v1.2.3, example.com/d",
+			verify:     true,
+			want: UpdatePolicy{
+				Aggressive: true,
+				PatchOnly:  true,
+				DirectOnly: true,
+				Allow:      []string{"golang.org/x/...", "example.com/a"},
+				Deny:       []string{"example.com/b"},
+				Pinned:     map[string]string{"example.com/c": "v1.2.3", "example.com/d": ""},
+				Verify:     true,
+			},
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			AggressiveFlag = &tt.aggressive
+			UpdateAllowFlag = &tt.allow
+			UpdateDenyFlag = &tt.deny
+			UpdateDirectOnlyFlag = &tt.directOnly
+			UpdatePatchOnlyFlag = &tt.patchOnly
+			UpdatePinFlag = &tt.pin
+			UpdateVerifyFlag = &tt.verify
+			if got := FlagUpdatePolicy(); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("FlagUpdatePolicy() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestUpdatePolicy_validate(t *testing.T) {
+	tests := map[string]struct {
+		pinned map[string]string
+		want   bool
+	}{
+		"no pins":         {pinned: nil, want: true},
+		"good pins":       {pinned: map[string]string{"example.com/a": "v1.2.3", "example.com/b": "v0.0.0-20240101000000-abcdefabcdef"}, want: true},
+		"missing version": {pinned: map[string]string{"example.com/a": ""}, want: false},
+		"bad version":     {pinned: map[string]string{"example.com/a": "latest"}, want: false},
+		"missing path":    {pinned: map[string]string{"": "v1.0.0"}, want: false},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			if got := (UpdatePolicy{Pinned: tt.pinned}).validate(); got != tt.want {
+				t.Errorf("validate() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestUpdatePolicy_updateCommands(t *testing.T) {
+	originalCachedWorkingDir := CachedWorkingDir
+	defer func() {
+		CachedWorkingDir = originalCachedWorkingDir
+	}()
+	// keeps WorkingDir() from getting exercised
+	CachedWorkingDir = "work"
+	goMod := "module example.com/m\n\nrequire (\n" +
+		"\texample.com/a v1.0.0\n" +
+		"\texample.com/b v1.0.0 // indirect\n" +
+		"\tgolang.org/x/mod v0.1.0\n" +
+		"\tgolang.org/x/tools v0.1.0 // indirect\n" +
+		")\n"
+	snapshot := moduleSnapshot{goMod: []byte(goMod)}
+	tests := map[string]struct {
+		policy   UpdatePolicy
+		snapshot moduleSnapshot
+		want     []string
+		wantOk   bool
+	}{
+		"default": {
+			policy:   UpdatePolicy{},
+			snapshot: snapshot,
+			want:     []string{"go get -u ./..."},
+			wantOk:   true,
+		},
+		"patch only": {
+			policy:   UpdatePolicy{PatchOnly: true},
+			snapshot: snapshot,
+			want:     []string{"go get -u=patch ./..."},
+			wantOk:   true,
+		},
+		"unparseable go.mod": {
+			policy:   UpdatePolicy{DirectOnly: true},
+			snapshot: moduleSnapshot{goMod: []byte("modulo m\n")},
+			want:     nil,
+			wantOk:   false,
+		},
+		"direct only": {
+			policy:   UpdatePolicy{DirectOnly: true},
+			snapshot: snapshot,
+			want:     []string{"go get example.com/a@latest golang.org/x/mod@latest"},
+			wantOk:   true,
+		},
+		"allow, patch only": {
+			policy:   UpdatePolicy{PatchOnly: true, Allow: []string{"golang.org/x/..."}},
+			snapshot: snapshot,
+			want:     []string{"go get golang.org/x/mod@patch golang.org/x/tools@patch"},
+			wantOk:   true,
+		},
+		"deny": {
+			policy:   UpdatePolicy{Deny: []string{"golang.org/x/...", "example.com/b"}},
+			snapshot: snapshot,
+			want:     []string{"go get example.com/a@latest example.com/b@v1.0.0 golang.org/x/mod@v0.1.0 golang.org/x/tools@v0.1.0"},
+			wantOk:   true,
+		},
+		"pinned": {
+			policy: UpdatePolicy{
+				Pinned: map[string]string{"example.com/a": "v1.0.0", "example.com/b": "v0.9.0", "example.com/z": "v1.0.0"},
+			},
+			snapshot: snapshot,
+			want: []string{
+				"go get golang.org/x/mod@latest golang.org/x/tools@latest example.com/a@v1.0.0 example.com/b@v0.9.0",
+			},
+			wantOk: true,
+		},
+		"pinned at current version": {
+			policy:   UpdatePolicy{DirectOnly: true, Pinned: map[string]string{"example.com/b": "v1.0.0"}},
+			snapshot: snapshot,
+			want:     []string{"go get example.com/a@latest golang.org/x/mod@latest example.com/b@v1.0.0"},
+			wantOk:   true,
+		},
+		"only pins": {
+			policy: UpdatePolicy{
+				Allow:  []string{"example.com/z"},
+				Deny:   []string{"golang.org/x/mod"},
+				Pinned: map[string]string{"example.com/b": "v0.9.0"},
+			},
+			snapshot: snapshot,
+			want:     []string{"go get example.com/b@v0.9.0 golang.org/x/mod@v0.1.0"},
+			wantOk:   true,
+		},
+		"nothing to change": {
+			policy:   UpdatePolicy{Allow: []string{"example.com/z"}, Pinned: map[string]string{"example.com/b": "v1.0.0"}},
+			snapshot: snapshot,
+			want:     []string{},
+			wantOk:   true,
+		},
+		"nothing selected": {
+			policy:   UpdatePolicy{Allow: []string{"example.com/z"}},
+			snapshot: snapshot,
+			want:     []string{},
+			wantOk:   true,
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, gotOk := tt.policy.updateCommands(tt.snapshot)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("updateCommands() got = %v, want %v", got, tt.want)
+			}
+			if gotOk != tt.wantOk {
+				t.Errorf("updateCommands() gotOk = %v, want %v", gotOk, tt.wantOk)
+			}
+		})
+	}
+}
@@ -0,0 +1,46 @@
+package tools_build
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/goyek/goyek/v3"
+)
+
+// Copyright © 2026 Marc Johnson (marc.johnson27591@gmail.com)
+
+// VerifyModules checks every module in the working directory tree: 'go mod
+// verify' confirms that the downloaded dependencies match the hashes in
+// go.sum, and 'go mod tidy -diff' confirms that go.mod and go.sum are tidy,
+// printing the changes that tidying would make. Every module is checked;
+// returns false if any check fails
+func VerifyModules(a *goyek.A) bool {
+	dirs, err := RelevantDirs(matchModuleFile)
+	if err != nil {
+		return false
+	}
+	status := true
+	for _, dir := range dirs {
+		path := filepath.Join(WorkingDir(), dir)
+		fmt.Printf("%q: verifying dependencies\n", path)
+		verifyCommand := directedCommand{command: "go mod verify", dir: path}
+		if !verifyCommand.execute(a) {
+			status = false
+		}
+		fmt.Printf("%q: checking that go.mod and go.sum are tidy\n", path)
+		tidy, diff, stderr := cmdCapture(a, directedCommand{command: "go mod tidy -diff", dir: path})
+		if tidy {
+			continue
+		}
+		status = false
+		if diff != "" {
+			fmt.Fprintf(os.Stderr, "%q: go.mod and go.sum are not tidy; 'go mod tidy' would make these changes:\n", path)
+			printIt(diff)
+		}
+		if stderr != "" {
+			printIt(stderr)
+		}
+	}
+	return status
+}
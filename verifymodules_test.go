@@ -0,0 +1,95 @@
+package tools_build
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/goyek/goyek/v3"
+	"github.com/goyek/x/cmd"
+	"github.com/spf13/afero"
+)
+
+// Copyright © 2026 Marc Johnson (marc.johnson27591@gmail.com)
+
+func TestVerifyModules(t *testing.T) {
+	originalBuildFS := BuildFS
+	originalCachedWorkingDir := CachedWorkingDir
+	originalExecFn := ExecFn
+	originalCmdCapture := cmdCapture
+	defer func() {
+		BuildFS = originalBuildFS
+		CachedWorkingDir = originalCachedWorkingDir
+		ExecFn = originalExecFn
+		cmdCapture = originalCmdCapture
+	}()
+	BuildFS = afero.NewMemMapFs()
+	_ = BuildFS.MkdirAll("work/tools", dirMode)
+	_ = afero.WriteFile(BuildFS, "work/go.mod", []byte("module example.com/m\n"), fileMode)
+	_ = afero.WriteFile(BuildFS, "work/tools/go.mod", []byte("module example.com/m/tools\n"), fileMode)
+	_ = afero.WriteFile(BuildFS, "notADir", []byte("garbage"), fileMode)
+	tests := map[string]struct {
+		workDir        string
+		verifySucceeds bool
+		tidySucceeds   bool
+		tidyDiff       string
+		wantCommands   []string
+		want           bool
+	}{
+		"bad dir": {
+			workDir:      "notADir",
+			wantCommands: []string{},
+			want:         false,
+		},
+		"all good": {
+			workDir:        "work",
+			verifySucceeds: true,
+			tidySucceeds:   true,
+			wantCommands: []string{
+				"go mod verify", "go mod tidy -diff",
+				"go mod verify", "go mod tidy -diff",
+			},
+			want: true,
+		},
+		"verify fails": {
+			workDir:        "work",
+			verifySucceeds: false,
+			tidySucceeds:   true,
+			wantCommands: []string{
+				"go mod verify", "go mod tidy -diff",
+				"go mod verify", "go mod tidy -diff",
+			},
+			want: false,
+		},
+		"untidy": {
+			workDir:        "work",
+			verifySucceeds: true,
+			tidySucceeds:   false,
+			tidyDiff:       "diff current/go.mod tidy/go.mod\n-require example.com/a v1.0.0\n",
+			wantCommands: []string{
+				"go mod verify", "go mod tidy -diff",
+				"go mod verify", "go mod tidy -diff",
+			},
+			want: false,
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			CachedWorkingDir = tt.workDir
+			gotCommands := make([]string, 0)
+			ExecFn = func(_ *goyek.A, command string, _ ...cmd.Option) bool {
+				gotCommands = append(gotCommands, command)
+				return tt.verifySucceeds
+			}
+			cmdCapture = func(_ *goyek.A, dC directedCommand) (bool, string, string) {
+				gotCommands = append(gotCommands, dC.command)
+				return tt.tidySucceeds, tt.tidyDiff, ""
+			}
+			if got := VerifyModules(nil); got != tt.want {
+				t.Errorf("VerifyModules() = %v, want %v", got, tt.want)
+			}
+			if !reflect.DeepEqual(gotCommands, tt.wantCommands) {
+				t.Errorf("VerifyModules() commands = %v, want %v", gotCommands, tt.wantCommands)
+			}
+		})
+	}
+}
@@ -0,0 +1,222 @@
+package tools_build
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/goyek/goyek/v3"
+)
+
+// Copyright © 2026 Marc Johnson (marc.johnson27591@gmail.com)
+
+var (
+	// VetDisableFlag is a flag that disables a comma-delimited set of go vet analyzers
+	VetDisableFlag = flag.String(
+		"vetdisable",
+		"",
+		"set to a comma-delimited set of go vet analyzers to disable")
+	// VetEnableFlag is a flag that restricts go vet to a comma-delimited set of analyzers
+	VetEnableFlag = flag.String(
+		"vetenable",
+		"",
+		"set to a comma-delimited set of go vet analyzers to run (default all analyzers)")
+	// VetExcludeFlag is a flag that excludes a comma-delimited set of package patterns from go vet findings
+	VetExcludeFlag = flag.String(
+		"vetexclude",
+		"",
+		"set to a comma-delimited set of package patterns, such as example.com/m/internal/..., whose go vet findings are ignored")
+	// VetTagsFlag is a flag that sets a comma-delimited set of build tags for go vet
+	VetTagsFlag = flag.String(
+		"vettags",
+		"",
+		"set to a comma-delimited set of build tags to use when running go vet")
+	// VetToolFlag is a flag that names an alternative analysis tool for go vet to run
+	VetToolFlag = flag.String(
+		"vettool",
+		"",
+		"set to the name or path of an analysis tool, such as shadow, for go vet to run instead of its own analyzers")
+)
+
+// lookPathFn is the exec.LookPath function, set as a variable so that unit
+// tests can override
+var lookPathFn = exec.LookPath
+
+// VetOptions controls how go vet runs; the zero value runs go vet's default
+// analyzers and reports every finding
+type VetOptions struct {
+	// Enable lists the analyzers to run, such as "printf"; if empty, every
+	// analyzer runs, except those in Disable
+	Enable []string
+	// Disable lists the analyzers not to run
+	Disable []string
+	// VetTool, if not empty, names an analysis tool, such as shadow, that go
+	// vet runs instead of its own analyzers; the tool is found, if it is not
+	// a path, in the directories named by the PATH environment variable. The
+	// tool must already be installed, for example by calling Install(a,
+	// "golang.org/x/tools/go/analysis/passes/shadow/cmd/shadow")
+	VetTool string
+	// Tags are the build tags to apply
+	Tags []string
+	// Exclude lists package patterns, either package paths or package paths
+	// followed by "/...", whose findings are ignored
+	Exclude []string
+}
+
+// FlagVetOptions returns the VetOptions specified by the command line flags
+func FlagVetOptions() VetOptions {
+	return VetOptions{
+		Enable:  splitList(*VetEnableFlag),
+		Disable: splitList(*VetDisableFlag),
+		VetTool: *VetToolFlag,
+		Tags:    splitList(*VetTagsFlag),
+		Exclude: splitList(*VetExcludeFlag),
+	}
+}
+
+// vetToolPath returns the absolute path of the vet tool
+func (vo VetOptions) vetToolPath() (string, bool) {
+	path, err := lookPathFn(vo.VetTool)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "cannot find the vet tool %q: %v\n", vo.VetTool, err)
+		return "", false
+	}
+	absolutePath, err := filepath.Abs(path)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "cannot find the vet tool %q: %v\n", vo.VetTool, err)
+		return "", false
+	}
+	return absolutePath, true
+}
+
+// validate reports analyzers that are both enabled and disabled, and a vet
+// tool that cannot be found
+func (vo VetOptions) validate() bool {
+	valid := true
+	for _, analyzer := range vo.Enable {
+		if slices.Contains(vo.Disable, analyzer) {
+			fmt.Fprintf(os.Stderr, "cannot both enable and disable the %q analyzer\n", analyzer)
+			valid = false
+		}
+	}
+	if vo.VetTool != "" {
+		if _, found := vo.vetToolPath(); !found {
+			valid = false
+		}
+	}
+	return valid
+}
+
+// command assembles the go vet command line described by the options
+func (vo VetOptions) command() string {
+	cmdParts := []string{"go", "vet", "-json"}
+	if vo.VetTool != "" {
+		path, found := vo.vetToolPath()
+		if !found {
+			path = vo.VetTool
+		}
+		cmdParts = append(cmdParts, "-vettool="+quoteArg(path))
+	}
+	if len(vo.Tags) != 0 {
+		cmdParts = append(cmdParts, "-tags="+quoteArg(strings.Join(vo.Tags, ",")))
+	}
+	for _, analyzer := range vo.Enable {
+		cmdParts = append(cmdParts, "-"+quoteArg(analyzer))
+	}
+	for _, analyzer := range vo.Disable {
+		cmdParts = append(cmdParts, "-"+quoteArg(analyzer)+"=false")
+	}
+	cmdParts = append(cmdParts, "./...")
+	return strings.Join(cmdParts, " ")
+}
+
+// VetLinter runs 'go vet', as directed by its options
+type VetLinter struct {
+	Options VetOptions
+}
+
+// Name returns "vet"
+func (VetLinter) Name() string {
+	return "vet"
+}
+
+// Install validates the options; go vet itself is part of the go toolchain
+func (vl VetLinter) Install(_ *goyek.A) bool {
+	return vl.Options.validate()
+}
+
+// Command returns the go vet command line described by the options, with JSON
+// output
+func (vl VetLinter) Command() string {
+	return vl.Options.command()
+}
+
+// Parse decodes the JSON diagnostics that go vet writes to stdout (older
+// toolchains write them to stderr instead), omitting those in the packages
+// excluded by the options; in JSON mode, go vet fails only when the code cannot
+// be analyzed, such as when it cannot be built
+func (vl VetLinter) Parse(state bool, stdout, stderr string) (Findings, bool) {
+	if !state {
+		return nil, false
+	}
+	output := stdout
+	if strings.TrimSpace(output) == "" {
+		output = stderr
+	}
+	return parseVetJSON(output, vl.Options.Exclude)
+}
+
+// parseVetJSON decodes the output of 'go vet -json'; older toolchains
+// interleave '# package' comment lines with the JSON written by each package's
+// analysis
+func parseVetJSON(output string, excluded []string) (Findings, bool) {
+	lines := make([]string, 0)
+	for line := range strings.SplitSeq(output, "\n") {
+		if !strings.HasPrefix(line, "#") {
+			lines = append(lines, line)
+		}
+	}
+	return parseAnalysisJSONExcluding("vet", SeverityWarning, strings.Join(lines, "\n"), excluded)
+}
+
+// VetFindings runs go vet, as directed by the options, in every module in the
+// working directory tree, and returns the findings; returns false if the
+// options are invalid or go vet cannot be run in any module
+func VetFindings(a *goyek.A, options VetOptions) (Findings, bool) {
+	linter := VetLinter{Options: options}
+	if !linter.Install(a) {
+		return nil, false
+	}
+	dirs, err := RelevantDirs(matchModuleFile)
+	if err != nil {
+		return nil, false
+	}
+	all := make(Findings, 0)
+	for _, dir := range dirs {
+		path := filepath.Join(WorkingDir(), dir)
+		fmt.Printf("%q: running go vet\n", path)
+		findings, ok := runLinterIn(a, linter, path)
+		if !ok {
+			return nil, false
+		}
+		all = append(all, findings...)
+	}
+	return all, true
+}
+
+// Vet runs go vet in every module in the working directory tree, as directed
+// by the command line flags (see FlagVetOptions), and reports its findings
+// (see reportFindings); returns false on failure, or if there are any findings
+func Vet(a *goyek.A) bool {
+	return VetWithOptions(a, FlagVetOptions())
+}
+
+// VetWithOptions works like Vet, running go vet as directed by the options
+func VetWithOptions(a *goyek.A, options VetOptions) bool {
+	findings, ok := VetFindings(a, options)
+	return ok && reportFindings("vet", findings) && len(findings) == 0
+}
@@ -0,0 +1,265 @@
+package tools_build
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/goyek/goyek/v3"
+	"github.com/spf13/afero"
+)
+
+// Copyright © 2026 Marc Johnson (marc.johnson27591@gmail.com)
+
+func TestFlagVetOptions(t *testing.T) {
+	originalVetDisableFlag := VetDisableFlag
+	originalVetEnableFlag := VetEnableFlag
+	originalVetExcludeFlag := VetExcludeFlag
+	originalVetTagsFlag := VetTagsFlag
+	originalVetToolFlag := VetToolFlag
+	defer func() {
+		VetDisableFlag = originalVetDisableFlag
+		VetEnableFlag = originalVetEnableFlag
+		VetExcludeFlag = originalVetExcludeFlag
+		VetTagsFlag = originalVetTagsFlag
+		VetToolFlag = originalVetToolFlag
+	}()
+	disable := "composites, structtag"
+	VetDisableFlag = &disable
+	enable := "printf"
+	VetEnableFlag = &enable
+	exclude := "example.com/m/gen/..."
+	VetExcludeFlag = &exclude
+	tags := "integration"
+	VetTagsFlag = &tags
+	tool := "shadow"
+	VetToolFlag = &tool
+	want := VetOptions{
+		Enable:  []string{"printf"},
+		Disable: []string{"composites", "structtag"},
+		VetTool: "shadow",
+		Tags:    []string{"integration"},
+		Exclude: []string{"example.com/m/gen/..."},
+	}
+	if got := FlagVetOptions(); !reflect.DeepEqual(got, want) {
+		t.Errorf("FlagVetOptions() = %v, want %v", got, want)
+	}
+}
+
+func TestVetOptions(t *testing.T) {
+	originalLookPathFn := lookPathFn
+	defer func() {
+		lookPathFn = originalLookPathFn
+	}()
+	lookPathFn = func(file string) (string, error) {
+		if file == "shadow" {
+			return "/go/bin/shadow", nil
+		}
+		return "", errors.New("executable file not found in $PATH")
+	}
+	tests := map[string]struct {
+		options     VetOptions
+		wantValid   bool
+		wantCommand string
+	}{
+		"defaults": {
+			options:     VetOptions{},
+			wantValid:   true,
+			wantCommand: "go vet -json ./...",
+		},
+		"analyzers": {
+			options:     VetOptions{Enable: []string{"printf", "copylocks"}, Disable: []string{"composites"}},
+			wantValid:   true,
+			wantCommand: "go vet -json -printf -copylocks -composites=false ./...",
+		},
+		"conflicting analyzers": {
+			options:     VetOptions{Enable: []string{"printf"}, Disable: []string{"printf"}},
+			wantValid:   false,
+			wantCommand: "go vet -json -printf -printf=false ./...",
+		},
+		"vet tool and tags": {
+			options:     VetOptions{VetTool: "shadow", Tags: []string{"integration", "linux"}, Enable: []string{"strict"}},
+			wantValid:   true,
+			wantCommand: "go vet -json -vettool=/go/bin/shadow -tags=integration,linux -strict ./...",
+		},
+		"missing vet tool": {
+			options:     VetOptions{VetTool: "nosuchtool"},
+			wantValid:   false,
+			wantCommand: "go vet -json -vettool=nosuchtool ./...",
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			if got := tt.options.validate(); got != tt.wantValid {
+				t.Errorf("validate() = %v, want %v", got, tt.wantValid)
+			}
+			if got := tt.options.command(); got != tt.wantCommand {
+				t.Errorf("command() = %q, want %q", got, tt.wantCommand)
+			}
+		})
+	}
+}
+
+func TestVetLinter(t *testing.T) {
+	if got, want := (VetLinter{}).Command(), "go vet -json ./..."; got != want {
+		t.Errorf("Command() = %q, want %q", got, want)
+	}
+	if !(VetLinter{}).Install(nil) {
+		t.Errorf("Install() = false, want true")
+	}
+	tests := map[string]struct {
+		state  bool
+		stdout string
+		stderr string
+		want   Findings
+		wantOk bool
+	}{
+		"cannot run": {
+			state:  false,
+			stderr: "go: no go.mod file",
+			wantOk: false,
+		},
+		"clean": {
+			state:  true,
+			want:   Findings{},
+			wantOk: true,
+		},
+		"excluded package": {
+			state:  true,
+			stdout: `{"example.com/m/gen [example.com/m/gen.test]": {"printf": [{"posn": "gen/a.go:4:2", "message": "wrong verb"}]}}` + "\n",
+			want:   Findings{},
+			wantOk: true,
+		},
+		"build fails": {
+			state:  false,
+			stdout: `{"example.com/m": {"printf": [{"posn": "a.go:4:2", "message": "wrong verb"}]}}` + "\n",
+			stderr: "# example.com/m/b\nvet: b/b.go:3:9: expected ')', found '{'\n",
+			wantOk: false,
+		},
+		"diagnostics": {
+			state: true,
+			stdout: `{
+	"example.com/m": {
+		"printf": [{"posn": "a.go:4:2", "end": "a.go:4:4", "message": "wrong verb"}]
+	},
+	"example.com/m/b": {
+		"copylocks": [{"posn": "b/b.go:9:1", "end": "b/b.go:9:5", "message": "copies lock"}]
+	}
+}
+`,
+			want: Findings{
+				{Tool: "vet", File: "a.go", Line: 4, Column: 2, Rule: "printf", Message: "wrong verb", Severity: SeverityWarning},
+				{Tool: "vet", File: "b/b.go", Line: 9, Column: 1, Rule: "copylocks", Message: "copies lock", Severity: SeverityWarning},
+			},
+			wantOk: true,
+		},
+		"older toolchain": {
+			state: true,
+			stderr: "# example.com/m\n" +
+				`{"example.com/m": {"printf": [{"posn": "a.go:4:2", "message": "wrong verb"}]}}` + "\n" +
+				"# example.com/m/b\n" +
+				`{"example.com/m/b": {"copylocks": [{"posn": "b/b.go:9:1", "message": "copies lock"}]}}` + "\n",
+			want: Findings{
+				{Tool: "vet", File: "a.go", Line: 4, Column: 2, Rule: "printf", Message: "wrong verb", Severity: SeverityWarning},
+				{Tool: "vet", File: "b/b.go", Line: 9, Column: 1, Rule: "copylocks", Message: "copies lock", Severity: SeverityWarning},
+			},
+			wantOk: true,
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, gotOk := VetLinter{Options: VetOptions{Exclude: []string{"example.com/m/gen/..."}}}.Parse(tt.state, tt.stdout, tt.stderr)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Parse() = %v, want %v", got, tt.want)
+			}
+			if gotOk != tt.wantOk {
+				t.Errorf("Parse() ok = %v, want %v", gotOk, tt.wantOk)
+			}
+		})
+	}
+}
+
+func TestVet(t *testing.T) {
+	originalBuildFS := BuildFS
+	originalCachedWorkingDir := CachedWorkingDir
+	originalCmdCapture := cmdCapture
+	defer func() {
+		BuildFS = originalBuildFS
+		CachedWorkingDir = originalCachedWorkingDir
+		cmdCapture = originalCmdCapture
+		delete(reportedFindings, "vet")
+	}()
+	BuildFS = afero.NewMemMapFs()
+	_ = BuildFS.MkdirAll("work/tools", dirMode)
+	_ = afero.WriteFile(BuildFS, "work/go.mod", []byte("module example.com/m\n"), fileMode)
+	_ = afero.WriteFile(BuildFS, "work/tools/go.mod", []byte("module example.com/m/tools\n"), fileMode)
+	_ = afero.WriteFile(BuildFS, "notADir", []byte("garbage"), fileMode)
+	tests := map[string]struct {
+		workDir   string
+		options   VetOptions
+		outputs   map[string]string
+		failDir   string
+		wantDirs  []string
+		wantCount int
+		want      bool
+	}{
+		"invalid options": {
+			workDir:  "work",
+			options:  VetOptions{Enable: []string{"printf"}, Disable: []string{"printf"}},
+			wantDirs: []string{},
+			want:     false,
+		},
+		"bad dir": {
+			workDir:  "notADir",
+			wantDirs: []string{},
+			want:     false,
+		},
+		"clean": {
+			workDir:  "work",
+			outputs:  map[string]string{},
+			wantDirs: []string{"work", "work/tools"},
+			want:     true,
+		},
+		"go vet cannot run": {
+			workDir:  "work",
+			failDir:  "work",
+			wantDirs: []string{"work"},
+			want:     false,
+		},
+		"findings": {
+			workDir: "work",
+			outputs: map[string]string{
+				"work/tools": `{"example.com/m/tools": {"printf": [{"posn": "tools/a.go:4:2", "message": "wrong verb"}]}}`,
+			},
+			wantDirs:  []string{"work", "work/tools"},
+			wantCount: 1,
+			want:      false,
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			delete(reportedFindings, "vet")
+			CachedWorkingDir = tt.workDir
+			gotDirs := make([]string, 0)
+			cmdCapture = func(_ *goyek.A, dC directedCommand) (bool, string, string) {
+				gotDirs = append(gotDirs, dC.dir)
+				if dC.command != "go vet -json ./..." {
+					t.Errorf("Vet() sent unexpected command: %q", dC.command)
+				}
+				if dC.dir == tt.failDir {
+					return false, "", "go: cannot load module"
+				}
+				return true, tt.outputs[dC.dir], ""
+			}
+			if got := VetWithOptions(nil, tt.options); got != tt.want {
+				t.Errorf("VetWithOptions() = %v, want %v", got, tt.want)
+			}
+			if !reflect.DeepEqual(gotDirs, tt.wantDirs) {
+				t.Errorf("VetWithOptions() dirs = %v, want %v", gotDirs, tt.wantDirs)
+			}
+			if got := len(FindingsOf("vet")); got != tt.wantCount {
+				t.Errorf("VetWithOptions() findings = %d, want %d", got, tt.wantCount)
+			}
+		})
+	}
+}
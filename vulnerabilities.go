@@ -0,0 +1,194 @@
+package tools_build
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"os"
+	"slices"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/goyek/goyek/v3"
+)
+
+// Copyright © 2026 Marc Johnson (marc.johnson27591@gmail.com)
+
+// Vulnerability describes a known vulnerability affecting a module used by the
+// code
+type Vulnerability struct {
+	// ID is the OSV identifier, such as GO-2024-1234
+	ID string
+	// Aliases are other identifiers of the vulnerability, such as CVE IDs
+	Aliases []string
+	// Summary briefly describes the vulnerability
+	Summary string
+	// Module is the affected module's path
+	Module string
+	// Version is the version of the affected module that is in use
+	Version string
+	// FixedVersion is the earliest version of the module that fixes the
+	// vulnerability; it is empty if there is no fix
+	FixedVersion string
+	// Called is true if the code calls the vulnerable symbol; otherwise, the
+	// code only imports the vulnerable package or requires the vulnerable
+	// module
+	Called bool
+	// Symbol is the vulnerable function that is called, if Called is true
+	Symbol string
+	// File, Line and Column locate the code's call leading to the vulnerable
+	// symbol, if Called is true
+	File   string
+	Line   int
+	Column int
+}
+
+// govulncheckMessage is the subset of a message written by 'govulncheck
+// -format json' that is of interest
+type govulncheckMessage struct {
+	OSV     *govulncheckOSV     `json:"osv"`
+	Finding *govulncheckFinding `json:"finding"`
+}
+
+type govulncheckOSV struct {
+	ID      string   `json:"id"`
+	Aliases []string `json:"aliases"`
+	Summary string   `json:"summary"`
+}
+
+type govulncheckFinding struct {
+	OSV          string             `json:"osv"`
+	FixedVersion string             `json:"fixed_version"`
+	Trace        []govulncheckFrame `json:"trace"`
+}
+
+type govulncheckFrame struct {
+	Module   string `json:"module"`
+	Version  string `json:"version"`
+	Package  string `json:"package"`
+	Function string `json:"function"`
+	Receiver string `json:"receiver"`
+	Position *struct {
+		Filename string `json:"filename"`
+		Line     int    `json:"line"`
+		Column   int    `json:"column"`
+	} `json:"position"`
+}
+
+// symbol renders the frame's function as package.function or
+// package.receiver.function
+func (frame govulncheckFrame) symbol() string {
+	if frame.Receiver == "" {
+		return frame.Package + "." + frame.Function
+	}
+	return frame.Package + "." + strings.TrimPrefix(frame.Receiver, "*") + "." + frame.Function
+}
+
+// VulnerabilityFindings runs the govulncheck tool, after making sure that it
+// is up-to-date, and returns the known vulnerabilities affecting the code,
+// one per vulnerability and module, after printing them as a table. Returns
+// false if govulncheck cannot be run or its output cannot be parsed
+func VulnerabilityFindings(a *goyek.A) ([]Vulnerability, bool) {
+	if !Install(a, "golang.org/x/vuln/cmd/govulncheck") {
+		return nil, false
+	}
+	printIt("running vulnerability checks")
+	state, stdout, stderr := cmdCapture(a, directedCommand{command: "govulncheck -format json ./...", dir: WorkingDir()})
+	if !state {
+		if stderr != "" {
+			printIt(stderr)
+		}
+		return nil, false
+	}
+	vulnerabilities, ok := parseGovulncheckOutput(stdout)
+	if !ok {
+		return nil, false
+	}
+	printIt(vulnerabilityTable(vulnerabilities))
+	return vulnerabilities, true
+}
+
+// parseGovulncheckOutput decodes the stream of JSON messages written by
+// 'govulncheck -format json'; govulncheck writes a finding for each level
+// (module, package, and symbol) at which a vulnerability is detected, and
+// these are combined into a single Vulnerability per OSV ID and module,
+// sorted by OSV ID and module
+func parseGovulncheckOutput(output string) ([]Vulnerability, bool) {
+	decoder := json.NewDecoder(strings.NewReader(output))
+	entries := map[string]*govulncheckOSV{}
+	found := map[string]*Vulnerability{}
+	for {
+		var message govulncheckMessage
+		err := decoder.Decode(&message)
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "error %v parsing govulncheck output\n", err)
+			return nil, false
+		}
+		if message.OSV != nil {
+			entries[message.OSV.ID] = message.OSV
+		}
+		if message.Finding == nil || len(message.Finding.Trace) == 0 {
+			continue
+		}
+		vulnerable := message.Finding.Trace[0]
+		key := message.Finding.OSV + " " + vulnerable.Module
+		vulnerability, seen := found[key]
+		if !seen {
+			vulnerability = &Vulnerability{
+				ID:           message.Finding.OSV,
+				Module:       vulnerable.Module,
+				Version:      vulnerable.Version,
+				FixedVersion: message.Finding.FixedVersion,
+			}
+			found[key] = vulnerability
+		}
+		if vulnerable.Function != "" && !vulnerability.Called {
+			vulnerability.Called = true
+			vulnerability.Symbol = vulnerable.symbol()
+			// the last frame with a position is the call in the code
+			for _, frame := range slices.Backward(message.Finding.Trace) {
+				if frame.Position != nil {
+					vulnerability.File = frame.Position.Filename
+					vulnerability.Line = frame.Position.Line
+					vulnerability.Column = frame.Position.Column
+					break
+				}
+			}
+		}
+	}
+	vulnerabilities := make([]Vulnerability, 0, len(found))
+	for _, key := range slices.Sorted(maps.Keys(found)) {
+		vulnerability := *found[key]
+		if entry, known := entries[vulnerability.ID]; known {
+			vulnerability.Aliases = entry.Aliases
+			vulnerability.Summary = entry.Summary
+		}
+		vulnerabilities = append(vulnerabilities, vulnerability)
+	}
+	return vulnerabilities, true
+}
+
+// vulnerabilityTable renders the vulnerabilities as a compact table
+func vulnerabilityTable(vulnerabilities []Vulnerability) string {
+	if len(vulnerabilities) == 0 {
+		return "no known vulnerabilities found"
+	}
+	builder := &strings.Builder{}
+	w := tabwriter.NewWriter(builder, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "id\tmodule\tfound\tfixed\tcalled\tsymbol")
+	for _, vulnerability := range vulnerabilities {
+		called := "no"
+		if vulnerability.Called {
+			called = "yes"
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\n", vulnerability.ID, vulnerability.Module,
+			orDash(vulnerability.Version), orDash(vulnerability.FixedVersion), called, orDash(vulnerability.Symbol))
+	}
+	_ = w.Flush()
+	return strings.TrimSuffix(builder.String(), "\n")
+}
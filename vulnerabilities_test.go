@@ -0,0 +1,106 @@
+package tools_build
+
+import (
+	"reflect"
+	"testing"
+)
+
+// Copyright © 2026 Marc Johnson (marc.johnson27591@gmail.com)
+
+const govulncheckOutput = `{"config": {"protocol_version": "v1.0.0", "scan_level": "symbol"}}
+{"progress": {"message": "Scanning your code..."}}
+{"osv": {"id": "GO-2024-0001", "aliases": ["CVE-2024-0001"], "summary": "Crash in Parse"}}
+{"osv": {"id": "GO-2024-0002", "summary": "Leak in Open"}}
+{"finding": {"osv": "GO-2024-0001", "fixed_version": "v1.2.4", "trace": [{"module": "example.com/a", "version": "v1.2.3"}]}}
+{"finding": {"osv": "GO-2024-0001", "fixed_version": "v1.2.4", "trace": [{"module": "example.com/a", "version": "v1.2.3", "package": "example.com/a/p"}]}}
+{"finding": {"osv": "GO-2024-0001", "fixed_version": "v1.2.4", "trace": [
+	{"module": "example.com/a", "version": "v1.2.3", "package": "example.com/a/p", "function": "Parse", "receiver": "*Parser",
+		"position": {"filename": "p/parse.go", "line": 10, "column": 2}},
+	{"module": "example.com/m", "package": "example.com/m", "function": "main",
+		"position": {"filename": "main.go", "line": 20, "column": 5}}]}}
+{"finding": {"osv": "GO-2024-0002", "trace": [{"module": "example.com/b", "version": "v0.1.0", "package": "example.com/b"}]}}
+`
+
+func Test_parseGovulncheckOutput(t *testing.T) {
+	tests := map[string]struct {
+		output string
+		want   []Vulnerability
+		wantOk bool
+	}{
+		"bad output": {
+			output: "{",
+			want:   nil,
+			wantOk: false,
+		},
+		"nothing found": {
+			output: `{"config": {"scan_level": "symbol"}}`,
+			want:   []Vulnerability{},
+			wantOk: true,
+		},
+		"findings": {
+			output: govulncheckOutput,
+			want: []Vulnerability{
+				{
+					ID:           "GO-2024-0001",
+					Aliases:      []string{"CVE-2024-0001"},
+					Summary:      "Crash in Parse",
+					Module:       "example.com/a",
+					Version:      "v1.2.3",
+					FixedVersion: "v1.2.4",
+					Called:       true,
+					Symbol:       "example.com/a/p.Parser.Parse",
+					File:         "main.go",
+					Line:         20,
+					Column:       5,
+				},
+				{
+					ID:      "GO-2024-0002",
+					Summary: "Leak in Open",
+					Module:  "example.com/b",
+					Version: "v0.1.0",
+				},
+			},
+			wantOk: true,
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, gotOk := parseGovulncheckOutput(tt.output)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parseGovulncheckOutput() got = %v, want %v", got, tt.want)
+			}
+			if gotOk != tt.wantOk {
+				t.Errorf("parseGovulncheckOutput() gotOk = %v, want %v", gotOk, tt.wantOk)
+			}
+		})
+	}
+}
+
+func Test_vulnerabilityTable(t *testing.T) {
+	tests := map[string]struct {
+		vulnerabilities []Vulnerability
+		want            string
+	}{
+		"none": {
+			vulnerabilities: nil,
+			want:            "no known vulnerabilities found",
+		},
+		"some": {
+			vulnerabilities: []Vulnerability{
+				{ID: "GO-2024-0001", Module: "example.com/a", Version: "v1.2.3", FixedVersion: "v1.2.4", Called: true, Symbol: "example.com/a.Parse"},
+				{ID: "GO-2024-0002", Module: "example.com/b", Version: "v0.1.0"},
+			},
+			want: "" +
+				"id            module         found   fixed   called  symbol\n" +
+				"GO-2024-0001  example.com/a  v1.2.3  v1.2.4  yes     example.com/a.Parse\n" +
+				"GO-2024-0002  example.com/b  v0.1.0  -       no      -",
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			if got := vulnerabilityTable(tt.vulnerabilities); got != tt.want {
+				t.Errorf("vulnerabilityTable() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
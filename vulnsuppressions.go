@@ -0,0 +1,137 @@
+package tools_build
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/spf13/afero"
+)
+
+// Copyright © 2026 Marc Johnson (marc.johnson27591@gmail.com)
+
+// DefaultVulnerabilitySuppressionFile is the vulnerability suppression file
+// used when no other file is named
+const DefaultVulnerabilitySuppressionFile = "vulnerability-suppressions.json"
+
+// VulnerabilitySuppressionsFlag is a flag that names the vulnerability suppression file
+var VulnerabilitySuppressionsFlag = flag.String(
+	"vulnsuppressions",
+	DefaultVulnerabilitySuppressionFile,
+	"set to the name of the file listing vulnerabilities that are not to fail the build")
+
+const suppressionDateLayout = "2006-01-02"
+
+// VulnerabilitySuppression excuses a known vulnerability until it expires. A
+// suppression file is a JSON array of suppressions, such as
+//
+//	[
+//	  {
+//	    "id": "GO-2024-1234",
+//	    "justification": "no fix is available; the vulnerable code is only used in tests",
+//	    "expires": "2026-12-31"
+//	  }
+//	]
+type VulnerabilitySuppression struct {
+	// ID is the OSV identifier of the vulnerability
+	ID string `json:"id"`
+	// Justification explains why the vulnerability is suppressed
+	Justification string `json:"justification"`
+	// Expires is the last date, in YYYY-MM-DD format, on which the
+	// suppression applies
+	Expires string `json:"expires"`
+}
+
+// expired returns true if the suppression no longer applies on the
+// specified date
+func (vs VulnerabilitySuppression) expired(now time.Time) bool {
+	return now.Format(suppressionDateLayout) > vs.Expires
+}
+
+// validate reports a suppression that lacks an ID, a justification, or a
+// valid expiry date
+func (vs VulnerabilitySuppression) validate(fileName string) bool {
+	switch {
+	case vs.ID == "":
+		fmt.Fprintf(os.Stderr, "%q: a suppression has no id\n", fileName)
+	case strings.TrimSpace(vs.Justification) == "":
+		fmt.Fprintf(os.Stderr, "%q: the suppression of %s has no justification\n", fileName, vs.ID)
+	default:
+		if _, err := time.Parse(suppressionDateLayout, vs.Expires); err != nil {
+			fmt.Fprintf(os.Stderr, "%q: the suppression of %s has an invalid expiry date %q\n", fileName, vs.ID, vs.Expires)
+			return false
+		}
+		return true
+	}
+	return false
+}
+
+// readVulnerabilitySuppressions reads the named suppression file, which is
+// located relative to WorkingDir(); a missing file means that there are no
+// suppressions. Returns false, after reporting the error, if the file cannot
+// be read or parsed, or contains an invalid suppression
+func readVulnerabilitySuppressions(suppressionFile string) (map[string]VulnerabilitySuppression, bool) {
+	if isIllegalFileName(suppressionFile) {
+		fmt.Fprintf(os.Stderr, "cannot accept %q as a valid file name from which vulnerability suppressions can be read\n", suppressionFile)
+		return nil, false
+	}
+	fileName := filepath.Join(WorkingDir(), suppressionFile)
+	suppressions := map[string]VulnerabilitySuppression{}
+	if exists, _ := afero.Exists(BuildFS, fileName); !exists {
+		return suppressions, true
+	}
+	content, err := afero.ReadFile(BuildFS, fileName)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "error %v reading %q\n", err, fileName)
+		return nil, false
+	}
+	var entries []VulnerabilitySuppression
+	if err = json.Unmarshal(content, &entries); err != nil {
+		fmt.Fprintf(os.Stderr, "error %v parsing %q\n", err, fileName)
+		return nil, false
+	}
+	valid := true
+	for _, entry := range entries {
+		if !entry.validate(suppressionFile) {
+			valid = false
+			continue
+		}
+		suppressions[entry.ID] = entry
+	}
+	return suppressions, valid
+}
+
+// applyVulnerabilitySuppressions reports the called vulnerabilities that are
+// suppressed, and the suppressions that have expired; returns the number of
+// called vulnerabilities that are not suppressed, and false if any
+// suppression has expired
+func applyVulnerabilitySuppressions(vulnerabilities []Vulnerability,
+	suppressions map[string]VulnerabilitySuppression) (unsuppressed int, current bool) {
+	now := NowFn()
+	current = true
+	for _, vulnerability := range vulnerabilities {
+		if !vulnerability.Called {
+			continue
+		}
+		suppression, suppressed := suppressions[vulnerability.ID]
+		if !suppressed || suppression.expired(now) {
+			unsuppressed++
+			continue
+		}
+		fmt.Printf("%s (%s) is suppressed until %s: %s\n", vulnerability.ID, vulnerability.Module,
+			suppression.Expires, suppression.Justification)
+	}
+	for _, id := range slices.Sorted(maps.Keys(suppressions)) {
+		if suppression := suppressions[id]; suppression.expired(now) {
+			fmt.Fprintf(os.Stderr, "the suppression of %s expired on %s\n", id, suppression.Expires)
+			current = false
+		}
+	}
+	return unsuppressed, current
+}
@@ -0,0 +1,139 @@
+package tools_build
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/spf13/afero"
+)
+
+// Copyright © 2026 Marc Johnson (marc.johnson27591@gmail.com)
+
+func Test_readVulnerabilitySuppressions(t *testing.T) {
+	originalBuildFS := BuildFS
+	originalCachedWorkingDir := CachedWorkingDir
+	defer func() {
+		BuildFS = originalBuildFS
+		CachedWorkingDir = originalCachedWorkingDir
+	}()
+	CachedWorkingDir = "work"
+	tests := map[string]struct {
+		fileName string
+		content  string
+		want     map[string]VulnerabilitySuppression
+		wantOk   bool
+	}{
+		"bad file name": {
+			fileName: "../suppressions.json",
+			want:     nil,
+			wantOk:   false,
+		},
+		"missing file": {
+			fileName: "suppressions.json",
+			want:     map[string]VulnerabilitySuppression{},
+			wantOk:   true,
+		},
+		"unparseable": {
+			fileName: "suppressions.json",
+			content:  "{",
+			want:     nil,
+			wantOk:   false,
+		},
+		"invalid entries": {
+			fileName: "suppressions.json",
+			content: `[
+				{"justification": "no id", "expires": "2026-01-01"},
+				{"id": "GO-1", "expires": "2026-01-01"},
+				{"id": "GO-2", "justification": "bad date", "expires": "01/01/2026"},
+				{"id": "GO-3", "justification": "good", "expires": "2026-01-01"}
+			]`,
+			want: map[string]VulnerabilitySuppression{
+				"GO-3": {ID: "GO-3", Justification: "good", Expires: "2026-01-01"},
+			},
+			wantOk: false,
+		},
+		"valid": {
+			fileName: "suppressions.json",
+			content:  `[{"id": "GO-3", "justification": "good", "expires": "2026-01-01"}]`,
+			want: map[string]VulnerabilitySuppression{
+				"GO-3": {ID: "GO-3", Justification: "good", Expires: "2026-01-01"},
+			},
+			wantOk: true,
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			BuildFS = afero.NewMemMapFs()
+			_ = BuildFS.MkdirAll("work", dirMode)
+			if tt.content != "" {
+				_ = afero.WriteFile(BuildFS, "work/"+tt.fileName, []byte(tt.content), fileMode)
+			}
+			got, gotOk := readVulnerabilitySuppressions(tt.fileName)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("readVulnerabilitySuppressions() got = %v, want %v", got, tt.want)
+			}
+			if gotOk != tt.wantOk {
+				t.Errorf("readVulnerabilitySuppressions() gotOk = %v, want %v", gotOk, tt.wantOk)
+			}
+		})
+	}
+}
+
+func Test_applyVulnerabilitySuppressions(t *testing.T) {
+	originalNowFn := NowFn
+	defer func() {
+		NowFn = originalNowFn
+	}()
+	NowFn = func() time.Time { return time.Date(2026, 6, 15, 23, 59, 0, 0, time.UTC) }
+	vulnerabilities := []Vulnerability{
+		{ID: "GO-1", Module: "example.com/a", Called: true},
+		{ID: "GO-2", Module: "example.com/b", Called: true},
+		{ID: "GO-3", Module: "example.com/c"},
+	}
+	tests := map[string]struct {
+		suppressions     map[string]VulnerabilitySuppression
+		wantUnsuppressed int
+		wantCurrent      bool
+	}{
+		"no suppressions": {
+			suppressions:     map[string]VulnerabilitySuppression{},
+			wantUnsuppressed: 2,
+			wantCurrent:      true,
+		},
+		"all suppressed": {
+			suppressions: map[string]VulnerabilitySuppression{
+				"GO-1": {ID: "GO-1", Justification: "j", Expires: "2026-06-15"},
+				"GO-2": {ID: "GO-2", Justification: "j", Expires: "2027-01-01"},
+			},
+			wantUnsuppressed: 0,
+			wantCurrent:      true,
+		},
+		"expired": {
+			suppressions: map[string]VulnerabilitySuppression{
+				"GO-1": {ID: "GO-1", Justification: "j", Expires: "2026-06-14"},
+				"GO-2": {ID: "GO-2", Justification: "j", Expires: "2027-01-01"},
+			},
+			wantUnsuppressed: 1,
+			wantCurrent:      false,
+		},
+		"expired, unused": {
+			suppressions: map[string]VulnerabilitySuppression{
+				"GO-9": {ID: "GO-9", Justification: "j", Expires: "2025-01-01"},
+			},
+			wantUnsuppressed: 2,
+			wantCurrent:      false,
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			gotUnsuppressed, gotCurrent := applyVulnerabilitySuppressions(vulnerabilities, tt.suppressions)
+			if gotUnsuppressed != tt.wantUnsuppressed {
+				t.Errorf("applyVulnerabilitySuppressions() gotUnsuppressed = %d, want %d", gotUnsuppressed, tt.wantUnsuppressed)
+			}
+			if gotCurrent != tt.wantCurrent {
+				t.Errorf("applyVulnerabilitySuppressions() gotCurrent = %v, want %v", gotCurrent, tt.wantCurrent)
+			}
+		})
+	}
+}